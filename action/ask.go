@@ -0,0 +1,93 @@
+package action
+
+import (
+	"context"
+	"time"
+	"wen-ai-cli/cache"
+	"wen-ai-cli/common"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/model"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/usage"
+	"wen-ai-cli/wenai"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/gookit/i18n"
+)
+
+// askModel 创建聊天模型并流式输出回答，生成过程中按 Ctrl-C 可中断并保留已生成的内容，
+// command 为发起调用的子命令名称，用于记录 token 用量
+func askModel(ctx context.Context, command string, messages []*schema.Message) (*schema.Message, *model.HiddenParams, error) {
+	genCtx, stop := common.WithInterrupt(ctx)
+	defer stop()
+
+	candidates, err := wenai.GetCandidates()
+	if err != nil {
+		return nil, &model.HiddenParams{}, err
+	}
+	for _, candidate := range candidates {
+		candidate.Profile.Generation = setup.GetGenerationOptions(command, candidate.Profile.Generation)
+	}
+	primary := candidates[0]
+	reportOptions := wenai.ReportOptions{
+		ModelName: primary.Profile.GetModel(),
+		ShowUsage: setup.GetConfig().Usage.ShowInFooter,
+	}
+
+	// 命中缓存时直接回放缓存内容，输出效果与实时回答一致
+	cacheKey := ""
+	if setup.IsCacheEnabled() {
+		cacheKey = cache.Key(messages, primary.Profile, setup.GetConfig().AnswerConfig)
+		if entry, ok := cache.Get(cacheKey); ok {
+			logger.Debugf("cache hit: %s", cacheKey)
+			reportOptions.Cached = true
+			return wenai.ReportStream(genCtx, cache.ToStream(entry.Content, entry.ToolCalls), reportOptions)
+		}
+	}
+
+	// man 模式只展示帮助信息，不需要提交脚本
+	useTools := command != setup.ManualCmd && setup.GetConfig().AnswerConfig.EnableToolCalling
+	streamResult, answered, err := wenai.StreamWithFallback(genCtx, candidates, messages, wenai.ProposeCommandOptions(useTools)...)
+	if err != nil {
+		return nil, &model.HiddenParams{}, err
+	}
+	if answered.ProfileName != primary.ProfileName {
+		reportOptions.ModelName = answered.Profile.GetModel()
+		reportOptions.Fallback = true
+		logger.Infof(i18n.Dtr("llmFallbackAnswered"), answered.ProfileName, answered.Profile.GetModel())
+	}
+	fullMessage, hiddenParams, err := wenai.ReportStream(genCtx, streamResult, reportOptions)
+	recordUsage(command, answered, fullMessage)
+	// 只缓存当前配置档给出的完整回答，备用模型的回答不写入缓存
+	if err == nil && cacheKey != "" && !reportOptions.Fallback {
+		putErr := cache.Put(cacheKey, cache.Entry{
+			CreatedAt: time.Now(),
+			Model:     reportOptions.ModelName,
+			Content:   fullMessage.Content,
+			ToolCalls: fullMessage.ToolCalls,
+		})
+		if putErr != nil {
+			logger.Warnf("write cache failed: %v", putErr)
+		}
+	}
+	return fullMessage, hiddenParams, err
+}
+
+// recordUsage 将回答的 token 用量写入账本，服务未返回用量时跳过
+func recordUsage(command string, answered *wenai.Candidate, message *schema.Message) {
+	if message == nil || message.ResponseMeta == nil || message.ResponseMeta.Usage == nil {
+		return
+	}
+	tokenUsage := message.ResponseMeta.Usage
+	err := usage.Append(usage.Record{
+		Time:             time.Now(),
+		Command:          command,
+		Profile:          answered.ProfileName,
+		Model:            answered.Profile.GetModel(),
+		PromptTokens:     tokenUsage.PromptTokens,
+		CompletionTokens: tokenUsage.CompletionTokens,
+	})
+	if err != nil {
+		logger.Warnf("record usage failed: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"wen-ai-cli/cache"
+
+	"github.com/gookit/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+// NewCacheClearAction 创建 cache clear action执行
+func NewCacheClearAction() cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		count, err := cache.Clear()
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		fmt.Printf(i18n.Dtr("cacheCleared")+"\n", count)
+		return nil
+	}
+}
@@ -17,21 +17,16 @@ func NewConfigAction() cli.ActionFunc {
 		if lang != "" {
 			cfg.DefaultLang = lang
 		}
-		// 设置openaiApiKey
-		apiKey := cmd.String("apiKey")
-		if apiKey != "" {
-			cfg.OpenAI.APIKey = apiKey
+		// 模型相关配置写入当前使用的配置档，不存在时只允许创建默认配置档，其他配置档通过 config profile add 创建
+		profileName := setup.GetActiveProfileName()
+		if _, err := setup.GetProfile(profileName); err != nil && profileName != setup.DefaultProfileName {
+			return cli.Exit(err.Error(), exitCodeParams)
 		}
-		// 设置openaiBaseURL
-		baseURL := cmd.String("baseURL")
-		if baseURL != "" {
-			cfg.OpenAI.BaseURL = baseURL
-		}
-		// 设置openaiModel
-		model := cmd.String("model")
-		if model != "" {
-			cfg.OpenAI.Model = model
+		profile := cfg.Profiles[profileName]
+		if err := applyProfileFlags(cmd, &profile); err != nil {
+			return err
 		}
+		cfg.Profiles[profileName] = profile
 		// 保存配置
 		setup.SaveConfig(cfg)
 		fmt.Println("配置保存成功")
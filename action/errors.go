@@ -0,0 +1,27 @@
+package action
+
+import (
+	"wen-ai-cli/logger"
+	"wen-ai-cli/wenai"
+
+	"github.com/urfave/cli/v3"
+)
+
+// 运行脚本相关的退出码，与大模型调用错误的退出码互不重复
+const (
+	exitCodeParams   = 2  // 命令用法或配置错误：参数格式错误、缺少参数值或参数值无效、配置档无效
+	exitCodeNoScript = 8  // 关闭交互时回答中没有可运行的脚本
+	exitCodeRisk     = 9  // 关闭交互时拒绝运行超出 --allow-risk 等级的危险脚本
+	exitCodeSyntax   = 10 // 脚本语法检查失败且未修复
+)
+
+// exitWithError 将大模型调用错误转换为带有多语言提示和退出码的 cli 错误
+func exitWithError(err error) error {
+	wenErr := wenai.ClassifyError(err)
+	logger.Debugf("llm call failed: %v", err)
+	if wenErr.Kind == wenai.ErrorKindCanceled {
+		// 用户主动中断，回答已以中断结尾输出，不再额外提示
+		return cli.Exit("", wenErr.ExitCode())
+	}
+	return cli.Exit(wenErr.Message(), wenErr.ExitCode())
+}
@@ -5,7 +5,6 @@ import (
 	"strings"
 	"wen-ai-cli/logger"
 	"wen-ai-cli/setup"
-	"wen-ai-cli/wenai"
 	"wen-ai-cli/wenai/manual"
 
 	"github.com/urfave/cli/v3"
@@ -19,11 +18,9 @@ func NewWenManualAction() cli.ActionFunc {
 		question := strings.Join(cmd.Args().Slice(), " ")
 		answerConfig := setup.GetConfig().AnswerConfig
 		messages := manual.CreateOnceMessagesFromTemplate(cmdName, question, answerConfig.EnableExplain, answerConfig.EnableExtendParams, answerConfig.EnablePlatformPerception, answerConfig.EnableWorkUserAndDir)
-		cm := wenai.CreateOpenAIChatModel(ctx)
-		streamResult := wenai.Stream(ctx, cm, messages)
-		_, _, err := wenai.ReportStream(streamResult)
+		_, _, err := askModel(ctx, setup.ManualCmd, messages)
 		if err != nil {
-			logger.Errorf("ReportStream failed %v", err)
+			return exitWithError(err)
 		}
 		logger.Debug(i18n.Exit)
 		return nil
@@ -0,0 +1,82 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/model"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/wenai"
+
+	"github.com/gookit/i18n"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli/v3"
+)
+
+// NewModelsAction 创建 models action执行
+func NewModelsAction() cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		profileName := setup.GetActiveProfileName()
+		profile, err := setup.GetProfile(profileName)
+		if err != nil {
+			return cli.Exit(err.Error(), exitCodeParams)
+		}
+		// ollama 未配置地址时使用本地默认地址
+		if profile.BaseURL == "" && profile.GetProvider() != model.ProviderOllama {
+			return cli.Exit(fmt.Sprintf(i18n.Dtr("profileIncomplete"), profileName), exitCodeParams)
+		}
+
+		models, err := wenai.ListModels(ctx, profile)
+		if err != nil {
+			return exitWithError(err)
+		}
+		if cmd.Bool("json") {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(models)
+		}
+		if len(models) == 0 {
+			fmt.Println(i18n.Dtr("modelsEmpty"))
+			return nil
+		}
+
+		// 默认选中当前使用的模型
+		items := make([]string, len(models))
+		cursor := 0
+		for i, m := range models {
+			items[i] = m.ID
+			if m.Description != "" {
+				items[i] += "  (" + m.Description + ")"
+			}
+			if m.ID == profile.Model {
+				cursor = i
+			}
+		}
+		selector := promptui.Select{
+			Label:     fmt.Sprintf(i18n.Dtr("modelsSelect"), profileName),
+			Items:     items,
+			Size:      15,
+			CursorPos: cursor,
+			Searcher: func(input string, index int) bool {
+				return strings.Contains(strings.ToLower(items[index]), strings.ToLower(input))
+			},
+		}
+		index, _, err := selector.Run()
+		if err != nil {
+			logger.Errorf("Prompt failed %v", err)
+			return nil
+		}
+
+		chosen := models[index].ID
+		cfg := setup.GetConfig()
+		saved := cfg.Profiles[profileName]
+		saved.Model = chosen
+		cfg.Profiles[profileName] = saved
+		setup.SaveConfig(cfg)
+		fmt.Printf(i18n.Dtr("modelsSaved")+"\n", profileName, chosen)
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"wen-ai-cli/model"
+	"wen-ai-cli/setup"
+
+	"github.com/gookit/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+// NewProfileAddAction 创建 config profile add action执行
+func NewProfileAddAction() cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		name := cmd.Args().First()
+		if name == "" {
+			return cli.Exit(i18n.Dtr("profileNameRequired"), exitCodeParams)
+		}
+		cfg := setup.GetConfig()
+		// 已存在的配置档只覆盖传入的字段
+		profile := cfg.Profiles[name]
+		if err := applyProfileFlags(cmd, &profile); err != nil {
+			return err
+		}
+		cfg.Profiles[name] = profile
+		if cmd.Bool("use") {
+			cfg.ActiveProfile = name
+		}
+		setup.SaveConfig(cfg)
+		fmt.Printf(i18n.Dtr("profileSaved")+"\n", name)
+		return nil
+	}
+}
+
+// NewProfileListAction 创建 config profile list action执行
+func NewProfileListAction() cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		cfg := setup.GetConfig()
+		for _, name := range setup.GetProfileNames() {
+			profile := cfg.Profiles[name]
+			// 使用 * 标记当前默认配置档
+			mark := " "
+			if name == cfg.ActiveProfile {
+				mark = "*"
+			}
+			fmt.Printf("%s %-16s %-10s %-24s %s\n", mark, name, profile.GetProvider(), profile.GetModel(), profile.BaseURL)
+		}
+		return nil
+	}
+}
+
+// NewProfileUseAction 创建 config profile use action执行
+func NewProfileUseAction() cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		name := cmd.Args().First()
+		if name == "" {
+			return cli.Exit(i18n.Dtr("profileNameRequired"), exitCodeParams)
+		}
+		if _, err := setup.GetProfile(name); err != nil {
+			return cli.Exit(err.Error(), exitCodeParams)
+		}
+		cfg := setup.GetConfig()
+		cfg.ActiveProfile = name
+		setup.SaveConfig(cfg)
+		fmt.Printf(i18n.Dtr("profileSwitched")+"\n", name)
+		return nil
+	}
+}
+
+// NewProfileRemoveAction 创建 config profile remove action执行
+func NewProfileRemoveAction() cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		name := cmd.Args().First()
+		if name == "" {
+			return cli.Exit(i18n.Dtr("profileNameRequired"), exitCodeParams)
+		}
+		if _, err := setup.GetProfile(name); err != nil {
+			return cli.Exit(err.Error(), exitCodeParams)
+		}
+		cfg := setup.GetConfig()
+		// 不允许删除当前默认配置档，避免配置失效
+		if name == cfg.ActiveProfile {
+			return cli.Exit(fmt.Sprintf(i18n.Dtr("profileRemoveActive"), name), exitCodeParams)
+		}
+		delete(cfg.Profiles, name)
+		setup.SaveConfig(cfg)
+		fmt.Printf(i18n.Dtr("profileRemoved")+"\n", name)
+		return nil
+	}
+}
+
+// applyProfileFlags 将命令行中传入的配置档字段写入配置档，未传入的字段保持不变；
+// 服务类型无效时返回带退出码的错误，不修改配置档
+func applyProfileFlags(cmd *cli.Command, profile *model.Profile) error {
+	provider := cmd.String("provider")
+	if provider != "" && !slices.Contains(model.Providers, provider) {
+		return cli.Exit(fmt.Sprintf(i18n.Dtr("providerInvalid"), provider, strings.Join(model.Providers, ", ")), exitCodeParams)
+	}
+	if apiKey := cmd.String("apiKey"); apiKey != "" {
+		profile.APIKey = apiKey
+	}
+	if baseURL := cmd.String("baseURL"); baseURL != "" {
+		profile.BaseURL = baseURL
+	}
+	if modelName := cmd.String("model"); modelName != "" {
+		profile.Model = modelName
+	}
+	if provider != "" {
+		profile.Provider = provider
+	}
+	// 布尔参数允许通过 --azure=false 关闭
+	if cmd.IsSet("azure") {
+		profile.Azure.Enabled = cmd.Bool("azure")
+	}
+	if deployment := cmd.String("deployment"); deployment != "" {
+		profile.Azure.Deployment = deployment
+	}
+	if apiVersion := cmd.String("apiVersion"); apiVersion != "" {
+		profile.Azure.APIVersion = apiVersion
+	}
+	return nil
+}
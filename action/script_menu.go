@@ -0,0 +1,297 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"wen-ai-cli/common"
+	"wen-ai-cli/execute"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/model"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/wenai"
+	"wen-ai-cli/wenai/chat"
+
+	"github.com/gookit/i18n"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli/v3"
+)
+
+// maxBlockPreviewLength 代码块选择列表中脚本预览的最大长度
+const maxBlockPreviewLength = 50
+
+// runScriptMenu 回答结束后的操作菜单。回答中有多个代码块时先选择要运行的代码块，
+// 也可以依次运行多个代码块，某个代码块未运行或执行失败时停止运行后续代码块。
+// 关闭交互时不展示菜单，直接补充参数并运行选中的代码块
+func runScriptMenu(ctx context.Context, hiddenParams *model.HiddenParams) error {
+	if setup.IsNonInteractive() {
+		return runNonInteractive(hiddenParams)
+	}
+	if len(hiddenParams.Blocks) <= 1 {
+		_, _, err := runBlockMenu(ctx, hiddenParams)
+		return err
+	}
+
+	indexes, err := selectBlocks(hiddenParams.Blocks)
+	if err != nil {
+		logger.Errorf("Prompt failed %v", err)
+		return nil
+	}
+	for n, index := range indexes {
+		hiddenParams.SelectBlock(index)
+		if len(indexes) > 1 {
+			logger.Infof(i18n.Dtr("scriptBlockRunning"), n+1, len(indexes), blockLabel(index, hiddenParams.Blocks[index]))
+		}
+		exitCode, ran, err := runBlockMenu(ctx, hiddenParams)
+		if err != nil {
+			return err
+		}
+		if !ran || exitCode != 0 {
+			if n < len(indexes)-1 {
+				logger.Warn(i18n.Dtr("scriptBlocksStopped"))
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// runNonInteractive 不经交互运行选中的代码块，参数来自 --param、环境变量或默认值。
+// 没有脚本或参数缺失时返回带退出码的错误，脚本执行失败时以脚本的退出码结束
+func runNonInteractive(hiddenParams *model.HiddenParams) error {
+	if hiddenParams.ShellCode == "" {
+		return cli.Exit(i18n.Dtr("noScriptToRun"), exitCodeNoScript)
+	}
+	shellCode, env, err := common.FillParams(hiddenParams)
+	if err != nil {
+		return cli.Exit(err.Error(), exitCodeParams)
+	}
+	syntaxErr := execute.CheckSyntax(shellCode)
+	if setup.IsDryRun() {
+		common.ShowRisk(shellCode)
+		execute.PrintDryRun(shellCode, env, syntaxErr)
+		return nil
+	}
+	if syntaxErr != nil {
+		return cli.Exit(fmt.Sprintf(i18n.Dtr("syntaxError"), syntaxErr), exitCodeSyntax)
+	}
+	if !common.ConfirmRisk(shellCode) {
+		return cli.Exit(i18n.Dtr("riskNotAllowed"), exitCodeRisk)
+	}
+	if exitCode := execute.ExecuteScript(shellCode, env...); exitCode != 0 {
+		return cli.Exit("", exitCode)
+	}
+	return nil
+}
+
+// selectBlocks 选择要运行的代码块，返回代码块下标，选择退出时返回空列表
+func selectBlocks(blocks []model.ScriptBlock) ([]int, error) {
+	items := make([]string, 0, len(blocks)+2)
+	for i, block := range blocks {
+		items = append(items, blockLabel(i, block))
+	}
+	runSeveral := i18n.Dtr("scriptBlocksRunSeveral")
+	exit := setup.GetI18n().Exit
+	items = append(items, runSeveral, exit)
+
+	// 默认选中最后一个代码块，与只有一个代码块时的行为一致
+	selector := promptui.Select{
+		Label:     fmt.Sprintf(i18n.Dtr("scriptBlockSelect"), len(blocks)),
+		Items:     items,
+		CursorPos: len(blocks) - 1,
+		HideHelp:  true,
+		Size:      min(len(items), 10),
+	}
+	index, result, err := selector.Run()
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf(setup.GetI18n().YourChoice, result)
+	switch {
+	case index < len(blocks):
+		return []int{index}, nil
+	case result == runSeveral:
+		return inputBlockIndexes(len(blocks))
+	default:
+		logger.Debug(exit)
+		return nil, nil
+	}
+}
+
+// inputBlockIndexes 输入要依次运行的代码块编号，支持逗号或空格分隔和 1-3 形式的区间
+func inputBlockIndexes(count int) ([]int, error) {
+	prompt := promptui.Prompt{
+		Label:       i18n.Dtr("scriptBlocksInput"),
+		HideEntered: true,
+		Validate: func(input string) error {
+			_, err := parseBlockIndexes(input, count)
+			return err
+		},
+	}
+	input, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+	return parseBlockIndexes(input, count)
+}
+
+// parseBlockIndexes 将从 1 开始的编号列表解析为代码块下标
+func parseBlockIndexes(input string, count int) ([]int, error) {
+	invalid := errors.New(fmt.Sprintf(i18n.Dtr("scriptBlocksInvalid"), count))
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == '，' || r == ' '
+	})
+	if len(fields) == 0 {
+		return nil, invalid
+	}
+	var indexes []int
+	for _, field := range fields {
+		lowText, highText, isRange := strings.Cut(field, "-")
+		if !isRange {
+			highText = lowText
+		}
+		low, err := strconv.Atoi(lowText)
+		if err != nil {
+			return nil, invalid
+		}
+		high, err := strconv.Atoi(highText)
+		if err != nil || low < 1 || high > count || low > high {
+			return nil, invalid
+		}
+		for n := low; n <= high; n++ {
+			indexes = append(indexes, n-1)
+		}
+	}
+	return indexes, nil
+}
+
+// blockLabel 代码块在选择列表中的展示文本：编号、标题和脚本第一行
+func blockLabel(index int, block model.ScriptBlock) string {
+	preview, _, _ := strings.Cut(block.ShellCode, "\n")
+	if utf8.RuneCountInString(preview) > maxBlockPreviewLength {
+		preview = string([]rune(preview)[:maxBlockPreviewLength]) + "…"
+	}
+	if block.Title == "" {
+		return fmt.Sprintf("%d. %s", index+1, preview)
+	}
+	return fmt.Sprintf("%d. %s · %s", index+1, block.Title, preview)
+}
+
+// runBlockMenu 选中代码块的操作菜单：有参数时补充参数运行，否则直接运行，也可以微调后运行。
+// 返回脚本退出码和脚本是否被运行，脚本因语法错误未运行且未修复时返回带退出码的错误
+func runBlockMenu(ctx context.Context, hiddenParams *model.HiddenParams) (int, bool, error) {
+	i18n := setup.GetI18n()
+	if hiddenParams.HasParameters() {
+		// 如果存在需要填充的参数，则提示用户，说明可以填充参数
+		result, err := execute.Prompt(i18n.SelectOperation, []string{i18n.FillParamsAndRun, i18n.AdjustAndRun, i18n.Exit})
+		if err != nil {
+			logger.Errorf("Prompt failed %v", err)
+			return 0, false, nil
+		}
+		// 记录用户选择
+		logger.Debugf(i18n.YourChoice, result)
+
+		// 根据选择执行相应操作
+		switch result {
+		case i18n.FillParamsAndRun:
+			shellCode, env, shouldExecute := common.HandleParamsCompletion(hiddenParams)
+			if shouldExecute {
+				return runScript(ctx, shellCode, env...)
+			}
+		case i18n.AdjustAndRun:
+			shellCode, env, shouldExecute := common.HandleScriptAdjustment(hiddenParams)
+			if shouldExecute {
+				return runScript(ctx, shellCode, env...)
+			}
+		default:
+			logger.Debug(i18n.Exit)
+		}
+		return 0, false, nil
+	}
+
+	if hiddenParams.ShellCode == "" {
+		// 如果脚本为空，则提示用户，说明无法解析答案
+		result, err := execute.Prompt(i18n.SelectOperation, []string{i18n.Exit})
+		if err != nil {
+			logger.Errorf("Prompt failed %v", err)
+			return 0, false, nil
+		}
+		logger.Debugf(i18n.YourChoice, result)
+		logger.Debug(i18n.Exit)
+		return 0, false, nil
+	}
+
+	// 如果脚本不为空，则提示用户，说明可以执行
+	logger.Debug(i18n.CanExecute)
+	result, err := execute.Prompt(i18n.SelectOperation, []string{i18n.RunNow, i18n.AdjustAndRun, i18n.Exit})
+	if err != nil {
+		logger.Errorf("Prompt failed %v", err)
+		return 0, false, nil
+	}
+	logger.Debugf(i18n.YourChoice, result)
+	switch result {
+	case i18n.RunNow:
+		return runScript(ctx, hiddenParams.ShellCode)
+	case i18n.AdjustAndRun:
+		shellCode, env, shouldExecute := common.HandleScriptAdjustment(hiddenParams)
+		if shouldExecute {
+			return runScript(ctx, shellCode, env...)
+		}
+	default:
+		logger.Debug(i18n.Exit)
+	}
+	return 0, false, nil
+}
+
+// runScript 检查语法、确认危险命令后执行脚本，试运行时只展示将要执行的内容。
+// 语法检查失败时不执行脚本，可以请模型修复。返回脚本退出码和脚本是否被运行，
+// 语法检查失败且未修复时返回带退出码的错误
+func runScript(ctx context.Context, shellCode string, env ...string) (int, bool, error) {
+	syntaxErr := execute.CheckSyntax(shellCode)
+	if setup.IsDryRun() {
+		common.ShowRisk(shellCode)
+		execute.PrintDryRun(shellCode, env, syntaxErr)
+		return 0, true, nil
+	}
+	if syntaxErr != nil {
+		return 0, false, fixSyntax(ctx, shellCode, syntaxErr)
+	}
+	if !common.ConfirmRisk(shellCode) {
+		return 0, false, nil
+	}
+	return execute.ExecuteScript(shellCode, env...), true, nil
+}
+
+// fixSyntax 展示语法错误，用户选择后请模型修复脚本，并为修复后的回答重新打开操作菜单。
+// 未修复时返回语法检查失败的退出码，语法错误已输出，不再重复提示
+func fixSyntax(ctx context.Context, shellCode string, syntaxErr error) error {
+	logger.Errorf(i18n.Dtr("syntaxError"), syntaxErr)
+	notFixed := cli.Exit("", exitCodeSyntax)
+	askFix := i18n.Dtr("syntaxAskFix")
+	exit := setup.GetI18n().Exit
+	result, err := execute.Prompt(i18n.Dtr("syntaxBlocked"), []string{askFix, exit})
+	if err != nil {
+		logger.Errorf("Prompt failed %v", err)
+		return notFixed
+	}
+	logger.Debugf(setup.GetI18n().YourChoice, result)
+	if result != askFix {
+		logger.Debug(exit)
+		return notFixed
+	}
+
+	// secret 类型参数在脚本中是环境变量引用，不会把参数值发给模型
+	question := fmt.Sprintf(i18n.Dtr("syntaxFixQuestion"), syntaxErr) + "\n```\n" + shellCode + "\n```"
+	answerConfig := setup.GetConfig().AnswerConfig
+	messages := chat.CreateOnceMessagesFromTemplate(question, answerConfig.EnableExplain, answerConfig.EnableExtendParams, answerConfig.EnablePlatformPerception, answerConfig.EnableWorkUserAndDir)
+	_, hiddenParams, err := askModel(ctx, setup.OnceCmd, messages)
+	if err != nil {
+		logger.Error(wenai.ClassifyError(err).Message())
+		return notFixed
+	}
+	fmt.Println("--------------------------------")
+	return runScriptMenu(ctx, hiddenParams)
+}
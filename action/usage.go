@@ -0,0 +1,57 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+	"wen-ai-cli/usage"
+
+	"github.com/gookit/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+// NewUsageAction 创建 usage action执行
+func NewUsageAction() cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		groupBy := cmd.String("by")
+		switch groupBy {
+		case usage.GroupByDay, usage.GroupByProfile, usage.GroupByModel, usage.GroupByCommand:
+		default:
+			return cli.Exit(fmt.Sprintf(i18n.Dtr("usageByInvalid"), groupBy), exitCodeParams)
+		}
+		since := time.Now().AddDate(0, 0, -int(cmd.Int("days")))
+		records, err := usage.Load(since)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		if len(records) == 0 {
+			fmt.Println(i18n.Dtr("usageEmpty"))
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t\n", groupBy, i18n.Dtr("usageCalls"), i18n.Dtr("usagePrompt"), i18n.Dtr("usageCompletion"), i18n.Dtr("usageTotal"), i18n.Dtr("usageCost"))
+		total := usage.Summary{Key: i18n.Dtr("usageTotal")}
+		for _, summary := range usage.Summarize(records, groupBy) {
+			printSummary(w, &summary)
+			total.Calls += summary.Calls
+			total.PromptTokens += summary.PromptTokens
+			total.CompletionTokens += summary.CompletionTokens
+			total.Cost += summary.Cost
+			total.Priced = total.Priced || summary.Priced
+		}
+		printSummary(w, &total)
+		return w.Flush()
+	}
+}
+
+// printSummary 打印一行汇总，未配置单价时费用显示为 -
+func printSummary(w *tabwriter.Writer, summary *usage.Summary) {
+	cost := "-"
+	if summary.Priced {
+		cost = usage.FormatCost(summary.Cost)
+	}
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\t\n", summary.Key, summary.Calls, summary.PromptTokens, summary.CompletionTokens, summary.PromptTokens+summary.CompletionTokens, cost)
+}
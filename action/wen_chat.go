@@ -3,7 +3,6 @@ package action
 import (
 	"context"
 	"strings"
-	"wen-ai-cli/common"
 	"wen-ai-cli/execute"
 	"wen-ai-cli/logger"
 	"wen-ai-cli/setup"
@@ -12,12 +11,17 @@ import (
 	"wen-ai-cli/wenai"
 
 	"github.com/cloudwego/eino/schema"
+	"github.com/gookit/i18n"
 	"github.com/urfave/cli/v3"
 )
 
 // NewWenChatAction 创建 chat action执行
 func NewWenChatAction() cli.ActionFunc {
 	return func(ctx context.Context, cmd *cli.Command) error {
+		// 对话模式需要持续输入，不支持关闭交互
+		if setup.IsNonInteractive() {
+			return cli.Exit(i18n.Dtr("chatNonInteractive"), exitCodeParams)
+		}
 		// 获取配置信息
 		answerConfig := setup.GetConfig().AnswerConfig
 		// 获取语言包
@@ -47,14 +51,20 @@ func NewWenChatAction() cli.ActionFunc {
 			// 创建聊天消息模板
 
 			messages := chat.CreateMoreMessagesFromTemplate(question, chatHistory, answerConfig.EnableExplain, answerConfig.EnableExtendParams, answerConfig.EnablePlatformPerception, answerConfig.EnableWorkUserAndDir)
-			// 创建OpenAI聊天模型
-			cm := wenai.CreateOpenAIChatModel(ctx)
-			// 获取流式处理结果
-			streamResult := wenai.Stream(ctx, cm, messages)
-			// 解析流式结果，获取完整消息和隐藏参数
-			fullMessage, hiddenParams, err := wenai.ReportStream(streamResult)
+			// 流式输出回答，获取完整消息和隐藏参数
+			fullMessage, hiddenParams, err := askModel(ctx, setup.ChatCmd, messages)
 			if err != nil {
-				logger.Errorf("ReportStream failed %v", err)
+				wenErr := wenai.ClassifyError(err)
+				if wenErr.Kind == wenai.ErrorKindCanceled {
+					// 用户按下 Ctrl-C 中断回答，保留已收到的内容，可以继续对话
+					logger.Debug(wenErr.Message())
+				} else if fullMessage == nil {
+					// 请求未能建立，无法继续对话
+					return exitWithError(err)
+				} else {
+					// 回答中途出错，保留已收到的内容，用户可以继续对话
+					logger.Error(wenErr.Message())
+				}
 			}
 
 			// 打印帮助信息
@@ -77,85 +87,18 @@ func NewWenChatAction() cli.ActionFunc {
 
 			// 处理功能命令
 			if inputQuetion == "f" || inputQuetion == "F" {
-				// 如果有需要填充的参数
-				if hiddenParams.HasParameters() {
-					// 创建操作选择提示
-					result, err := execute.Prompt(i18n.SelectOperation, []string{i18n.FillParamsAndRun, i18n.AdjustAndRun, i18n.Exit})
-					if err != nil {
-						logger.Errorf("Prompt failed %v", err)
-						return nil
-					}
-
-					// 记录用户选择
-					logger.Debugf(i18n.YourChoice, result)
-
-					// 根据选择执行相应操作
-					if result == i18n.FillParamsAndRun {
-						shellCode, shouldExecute := common.HandleParamsCompletion(hiddenParams)
-						if shouldExecute {
-							execute.ExecuteScript(shellCode)
-						}
-					} else if result == i18n.AdjustAndRun {
-						script, shouldExecute := common.HandleScriptAdjustment(hiddenParams.ShellCode)
-						if shouldExecute {
-							execute.ExecuteScript(script)
-						}
-					} else {
-						logger.Debug(i18n.Exit)
-					}
-				} else {
-					if hiddenParams.ShellCode == "" {
-						// 如果脚本为空，则提示用户，说明无法解析答案
-						// 按照微调脚本进行处理
-						result, err := execute.Prompt(i18n.SelectOperation, []string{i18n.Exit})
-						if err != nil {
-							logger.Errorf("Prompt failed %v", err)
-							return nil
-						}
-						logger.Debugf(i18n.YourChoice, result)
-						if result == i18n.AdjustAndRun {
-							script, shouldExecute := common.HandleScriptAdjustment(hiddenParams.ShellCode)
-							if shouldExecute {
-								execute.ExecuteScript(script)
-							}
-						} else {
-							logger.Debug(i18n.Exit)
-						}
-					} else {
-						// 如果脚本不为空，则提示用户，说明可以执行
-						logger.Debug(i18n.CanExecute)
-						// 创建操作选择提示
-						result, err := execute.Prompt(i18n.SelectOperation, []string{i18n.RunNow, i18n.AdjustAndRun, i18n.Exit})
-						if err != nil {
-							logger.Errorf("Prompt failed %v", err)
-							return nil
-						}
-
-						// 记录用户选择
-						logger.Debugf(i18n.YourChoice, result)
-
-						// 根据选择执行相应操作
-						if result == i18n.RunNow {
-							execute.ExecuteScript(hiddenParams.ShellCode)
-						} else if result == i18n.AdjustAndRun {
-							script, shouldExecute := common.HandleScriptAdjustment(hiddenParams.ShellCode)
-							if shouldExecute {
-								execute.ExecuteScript(script)
-							}
-						} else {
-							logger.Debug(i18n.Exit)
-						}
-					}
-
-				}
-				return nil
+				// 选择代码块，补充参数后运行
+				return runScriptMenu(ctx, hiddenParams)
 			}
 
 			// 其他情况，继续对话，并更新聊天历史记录
 			// 保留最近10条消息
 			chatHistory = messages[max(1, len(messages)-10):]
-			// 添加最新消息到历史记录
-			chatHistory = append(chatHistory, fullMessage)
+			// 添加最新消息到历史记录，请求被中断时没有回答内容；
+			// 工具调用没有对应的工具结果消息，不放入历史记录
+			if fullMessage != nil {
+				chatHistory = append(chatHistory, &schema.Message{Role: fullMessage.Role, Content: fullMessage.Content})
+			}
 			// 更新问题为最新输入
 			question = inputQuetion
 		}
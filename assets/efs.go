@@ -1,8 +1,12 @@
 package assets
 
 import (
+	"bytes"
 	"embed"
+	"errors"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gookit/goutil/fsutil"
 )
@@ -10,8 +14,8 @@ import (
 //go:embed lang
 var fs embed.FS
 
-// CopyLangFiles 复制语言文件到指定目录
-func CopyLangFiles(targetLangDir string) error {
+// SyncLangFiles 同步语言文件到指定目录：缺少的文件直接复制，已有文件追加缺少的翻译，保留用户修改过的翻译
+func SyncLangFiles(targetLangDir string) error {
 	files, err := fs.ReadDir("lang")
 	if err != nil {
 		return err
@@ -27,7 +31,67 @@ func CopyLangFiles(targetLangDir string) error {
 			return err
 		}
 		copyToFilePath := filepath.Join(targetLangDir, file.Name())
-		fsutil.WriteFile(copyToFilePath, content, 0644)
+		existing, err := os.ReadFile(copyToFilePath)
+		if errors.Is(err, os.ErrNotExist) {
+			if err := fsutil.WriteFile(copyToFilePath, content, 0644); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		missing := missingLangLines(existing, content)
+		if len(missing) == 0 {
+			continue
+		}
+		if !bytes.HasSuffix(existing, []byte("\n")) && len(existing) > 0 {
+			existing = append(existing, '\n')
+		}
+		existing = append(existing, strings.Join(missing, "\n")+"\n"...)
+		if err := os.WriteFile(copyToFilePath, existing, 0644); err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// missingLangLines 返回内置语言文件中有而已有文件中没有的翻译行，保留内置文件中的顺序和分组注释
+func missingLangLines(existing, embedded []byte) []string {
+	keys := langKeys(existing)
+	var missing []string
+	comment := ""
+	for _, line := range strings.Split(string(embedded), "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			comment = line
+			continue
+		}
+		key, _, ok := strings.Cut(trimmed, "=")
+		if !ok || keys[strings.TrimSpace(key)] {
+			continue
+		}
+		if comment != "" {
+			missing = append(missing, "", comment)
+			comment = ""
+		}
+		missing = append(missing, line)
+	}
+	return missing
+}
+
+// langKeys 获取语言文件中已有的翻译键
+func langKeys(content []byte) map[string]bool {
+	keys := map[string]bool{}
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		if key, _, ok := strings.Cut(trimmed, "="); ok {
+			keys[strings.TrimSpace(key)] = true
+		}
+	}
+	return keys
+}
@@ -0,0 +1,55 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMissingLangLines(t *testing.T) {
+	existing := []byte("cliName = Custom\n# old\nusage=x\n")
+	embedded := []byte("cliName = WenAI CLI\nusage = Use\n\n# risk\nriskTitle = Risk\n; note\nriskHigh=High\n")
+	got := missingLangLines(existing, embedded)
+	want := []string{"", "# risk", "riskTitle = Risk", "", "; note", "riskHigh=High"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("missingLangLines() = %q, want %q", got, want)
+	}
+}
+
+func TestSyncLangFiles(t *testing.T) {
+	dir := t.TempDir()
+	enPath := filepath.Join(dir, "en.ini")
+	if err := os.WriteFile(enPath, []byte("cliName = Custom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SyncLangFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	// 缺少的文件直接复制
+	if _, err := os.Stat(filepath.Join(dir, "zh-CN.ini")); err != nil {
+		t.Fatalf("zh-CN.ini not copied: %v", err)
+	}
+	first, err := os.ReadFile(enPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(first)
+	if !strings.HasPrefix(content, "cliName = Custom\n") {
+		t.Fatalf("user translation not kept: %q", content[:40])
+	}
+	if strings.Count(content, "cliName") != 1 || !strings.Contains(content, "usage = ") {
+		t.Fatalf("missing keys not merged:\n%s", content)
+	}
+	// 再次同步时不重复追加
+	if err := SyncLangFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	second, err := os.ReadFile(enPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(second) != content {
+		t.Fatal("second sync changed the file")
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"wen-ai-cli/model"
+	"wen-ai-cli/setup"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Entry 缓存的一条回答
+type Entry struct {
+	CreatedAt time.Time         `json:"createdAt"`
+	Model     string            `json:"model"`
+	Content   string            `json:"content"`
+	ToolCalls []schema.ToolCall `json:"toolCalls,omitempty"` // 模型通过 propose_command 工具提交的脚本
+}
+
+// Key 根据渲染后的消息、配置档的服务类型、接口地址、模型名称和生成参数以及回答配置计算缓存 key，
+// 不同服务中的同名模型不共用缓存
+func Key(messages []*schema.Message, profile *model.Profile, answerConfig model.AnswerConfig) string {
+	h := sha256.New()
+	for _, msg := range messages {
+		h.Write([]byte(msg.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(msg.Content))
+		h.Write([]byte{0})
+	}
+	for _, field := range []string{profile.GetProvider(), profile.BaseURL, profile.GetModel()} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	configData, _ := json.Marshal(answerConfig)
+	h.Write(configData)
+	generationData, _ := json.Marshal(profile.Generation)
+	h.Write(generationData)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get 读取未过期的缓存回答
+func Get(key string) (*Entry, bool) {
+	data, err := os.ReadFile(entryPath(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	ttl := time.Duration(setup.GetConfig().Cache.TTLHours) * time.Hour
+	if ttl > 0 && time.Since(entry.CreatedAt) > ttl {
+		os.Remove(entryPath(key))
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put 写入缓存回答，并按容量上限淘汰最旧的缓存
+func Put(key string, entry Entry) error {
+	dir := setup.GetCacheDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(entryPath(key), data, 0644); err != nil {
+		return err
+	}
+	return evict(int64(setup.GetConfig().Cache.MaxSizeMB) * 1024 * 1024)
+}
+
+// Clear 删除全部缓存，返回删除的条目数
+func Clear() (int, error) {
+	files, err := filepath.Glob(filepath.Join(setup.GetCacheDir(), "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return 0, err
+		}
+	}
+	return len(files), nil
+}
+
+// ToStream 将缓存内容按行切分为流，工具调用放在最后一个分片，以便复用流式输出的打印和解析逻辑
+func ToStream(content string, toolCalls []schema.ToolCall) *schema.StreamReader[*schema.Message] {
+	var chunks []*schema.Message
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+		chunks = append(chunks, &schema.Message{Role: schema.Assistant, Content: line})
+	}
+	if len(toolCalls) > 0 {
+		chunks = append(chunks, &schema.Message{Role: schema.Assistant, ToolCalls: toolCalls})
+	}
+	return schema.StreamReaderFromArray(chunks)
+}
+
+func entryPath(key string) string {
+	return filepath.Join(setup.GetCacheDir(), key+".json")
+}
+
+// evict 缓存目录超出容量时按修改时间从旧到新删除，maxSize 为 0 时不限制
+func evict(maxSize int64) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(setup.GetCacheDir(), "*.json"))
+	if err != nil {
+		return err
+	}
+	infos := make([]os.FileInfo, 0, len(files))
+	var total int64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, info)
+		total += info.Size()
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+	for _, info := range infos {
+		if total <= maxSize {
+			break
+		}
+		if err := os.Remove(filepath.Join(setup.GetCacheDir(), info.Name())); err != nil {
+			return err
+		}
+		total -= info.Size()
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"testing"
+	"wen-ai-cli/model"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestKey(t *testing.T) {
+	messages := []*schema.Message{schema.SystemMessage("system"), schema.UserMessage("list files")}
+	answerConfig := model.AnswerConfig{}
+	base := model.Profile{Provider: model.ProviderOpenAI, BaseURL: "https://api.example.com/v1", Model: "qwen2.5"}
+	key := Key(messages, &base, answerConfig)
+
+	same := base
+	if got := Key(messages, &same, answerConfig); got != key {
+		t.Errorf("same profile got a different key")
+	}
+	// 未配置服务类型时按 openai 处理
+	defaultProvider := base
+	defaultProvider.Provider = ""
+	if got := Key(messages, &defaultProvider, answerConfig); got != key {
+		t.Errorf("empty provider should share the openai key")
+	}
+
+	temperature := float32(0.2)
+	for name, change := range map[string]func(*model.Profile){
+		"provider":    func(p *model.Profile) { p.Provider = model.ProviderOllama },
+		"base URL":    func(p *model.Profile) { p.BaseURL = "http://127.0.0.1:11434" },
+		"model":       func(p *model.Profile) { p.Model = "qwen2.5:7b" },
+		"temperature": func(p *model.Profile) { p.Generation.Temperature = &temperature },
+	} {
+		profile := base
+		change(&profile)
+		if got := Key(messages, &profile, answerConfig); got == key {
+			t.Errorf("changing the %s should change the key", name)
+		}
+	}
+	if got := Key(messages[:1], &base, answerConfig); got == key {
+		t.Errorf("changing the messages should change the key")
+	}
+}
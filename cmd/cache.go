@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"wen-ai-cli/action"
+	"wen-ai-cli/setup"
+
+	"github.com/gookit/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+// NewCacheCmd 创建 cache 命令
+func NewCacheCmd() *cli.Command {
+	return &cli.Command{
+		Name:  setup.CacheCmd,
+		Usage: i18n.Dtr("cacheCmdUsage"),
+		Commands: []*cli.Command{
+			{
+				Name:   "clear",
+				Usage:  i18n.Dtr("cacheClearUsage"),
+				Action: action.NewCacheClearAction(),
+			},
+		},
+	}
+}
@@ -14,31 +14,16 @@ func NewConfigCmd() *cli.Command {
 		Name:    setup.ConfigCmd,
 		Aliases: []string{setup.ConfigCmdAlias},
 		Usage:   i18n.Dtr("configMode"),
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "lang",
 				Aliases: []string{"l"},
 				Value:   "zh-CN",
 				Usage:   i18n.Dtr("configLang"),
 			},
-			&cli.StringFlag{
-				Name:    "apiKey",
-				Aliases: []string{"k"},
-				Value:   "",
-				Usage:   i18n.Dtr("configAk"),
-			},
-			&cli.StringFlag{
-				Name:    "baseURL",
-				Aliases: []string{"u"},
-				Value:   "",
-				Usage:   i18n.Dtr("configBaseURL"),
-			},
-			&cli.StringFlag{
-				Name:    "model",
-				Aliases: []string{"m"},
-				Value:   "",
-				Usage:   i18n.Dtr("configModel"),
-			},
+		}, newProfileFlags()...),
+		Commands: []*cli.Command{
+			NewProfileCmd(),
 		},
 		Action: action.NewConfigAction(),
 	}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"wen-ai-cli/action"
+	"wen-ai-cli/setup"
+
+	"github.com/gookit/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+// NewModelsCmd 创建 models 命令
+func NewModelsCmd() *cli.Command {
+	return &cli.Command{
+		Name:  setup.ModelsCmd,
+		Usage: i18n.Dtr("modelsCmdUsage"),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: i18n.Dtr("modelsJsonFlag"),
+			},
+		},
+		Action: action.NewModelsAction(),
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"wen-ai-cli/action"
+	"wen-ai-cli/setup"
+
+	"github.com/gookit/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+// NewProfileCmd 创建 config profile 命令
+func NewProfileCmd() *cli.Command {
+	return &cli.Command{
+		Name:  setup.ProfileCmd,
+		Usage: i18n.Dtr("profileMode"),
+		Commands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     i18n.Dtr("profileAdd"),
+				ArgsUsage: "<name>",
+				Flags: append(newProfileFlags(), &cli.BoolFlag{
+					Name:  "use",
+					Usage: i18n.Dtr("profileAddUse"),
+				}),
+				Action: action.NewProfileAddAction(),
+			},
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Usage:   i18n.Dtr("profileList"),
+				Action:  action.NewProfileListAction(),
+			},
+			{
+				Name:      "use",
+				Usage:     i18n.Dtr("profileUse"),
+				ArgsUsage: "<name>",
+				Action:    action.NewProfileUseAction(),
+			},
+			{
+				Name:      "remove",
+				Aliases:   []string{"rm"},
+				Usage:     i18n.Dtr("profileRemove"),
+				ArgsUsage: "<name>",
+				Action:    action.NewProfileRemoveAction(),
+			},
+		},
+	}
+}
+
+// newProfileFlags 创建配置档字段相关的参数，供 config 和 config profile add 共用
+func newProfileFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "apiKey",
+			Aliases: []string{"k"},
+			Value:   "",
+			Usage:   i18n.Dtr("configAk"),
+		},
+		&cli.StringFlag{
+			Name:    "baseURL",
+			Aliases: []string{"u"},
+			Value:   "",
+			Usage:   i18n.Dtr("configBaseURL"),
+		},
+		&cli.StringFlag{
+			Name:    "model",
+			Aliases: []string{"m"},
+			Value:   "",
+			Usage:   i18n.Dtr("configModel"),
+		},
+		&cli.StringFlag{
+			Name:  "provider",
+			Value: "",
+			Usage: i18n.Dtr("configProvider"),
+		},
+		&cli.BoolFlag{
+			Name:  "azure",
+			Usage: i18n.Dtr("configAzure"),
+		},
+		&cli.StringFlag{
+			Name:  "deployment",
+			Value: "",
+			Usage: i18n.Dtr("configAzureDeployment"),
+		},
+		&cli.StringFlag{
+			Name:  "apiVersion",
+			Value: "",
+			Usage: i18n.Dtr("configAzureAPIVersion"),
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"wen-ai-cli/action"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/usage"
+
+	"github.com/gookit/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+// NewUsageCmd 创建 usage 命令
+func NewUsageCmd() *cli.Command {
+	return &cli.Command{
+		Name:  setup.UsageCmd,
+		Usage: i18n.Dtr("usageCmdUsage"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "by",
+				Value: usage.GroupByDay,
+				Usage: i18n.Dtr("usageByFlag"),
+			},
+			&cli.IntFlag{
+				Name:  "days",
+				Value: 30,
+				Usage: i18n.Dtr("usageDaysFlag"),
+			},
+		},
+		Action: action.NewUsageAction(),
+	}
+}
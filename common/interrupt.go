@@ -0,0 +1,47 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// InterruptExitCode 连续两次 Ctrl-C 退出时的进程退出码
+const InterruptExitCode = 130
+
+// WithInterrupt 返回一个在收到 Ctrl-C 时取消的上下文，用于包裹单次大模型生成。
+// 第一次 Ctrl-C 只取消生成，调用 stop 之前再次 Ctrl-C 则直接退出进程。
+func WithInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigCh:
+			cancel()
+		case <-done:
+			return
+		}
+		// 生成已取消但调用方尚未处理完毕，再次 Ctrl-C 直接退出
+		select {
+		case <-sigCh:
+			fmt.Println()
+			os.Exit(InterruptExitCode)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+			cancel()
+		})
+	}
+	return ctx, stop
+}
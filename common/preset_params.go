@@ -0,0 +1,126 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/model"
+	"wen-ai-cli/placeholder"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/validate"
+
+	"github.com/gookit/i18n"
+)
+
+// paramEnvPrefix 预先指定参数值的环境变量前缀
+const paramEnvPrefix = "WENAI_PARAM_"
+
+// ParamEnvName 获取参数对应的环境变量名：WENAI_PARAM_ 加上大写的参数名称，字母和数字以外的字符替换为 _
+func ParamEnvName(name string) string {
+	return paramEnvPrefix + normalizeParamName(name)
+}
+
+// normalizeParamName 将参数名称转换为大写，连续的字母和数字以外的字符替换为一个 _
+func normalizeParamName(name string) string {
+	var builder strings.Builder
+	separator := false
+	for _, r := range strings.TrimSpace(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if separator && builder.Len() > 0 {
+				builder.WriteByte('_')
+			}
+			separator = false
+			builder.WriteRune(unicode.ToUpper(r))
+			continue
+		}
+		separator = true
+	}
+	return builder.String()
+}
+
+// presetValue 获取通过 --param 或 WENAI_PARAM_<NAME> 预先指定的参数值，--param 优先。
+// --param 的名称先按原样匹配，再按大小写和分隔符无关的方式匹配
+func presetValue(name string) (string, bool) {
+	overrides := setup.GetParamOverrides()
+	for i := len(overrides) - 1; i >= 0; i-- {
+		if overrides[i][0] == strings.TrimSpace(name) {
+			return overrides[i][1], true
+		}
+	}
+	normalized := normalizeParamName(name)
+	for i := len(overrides) - 1; i >= 0; i-- {
+		if normalizeParamName(overrides[i][0]) == normalized {
+			return overrides[i][1], true
+		}
+	}
+	if normalized == "" {
+		return "", false
+	}
+	return os.LookupEnv(ParamEnvName(name))
+}
+
+// validateParamValue 按参数类型校验参数值，enum 类型要求值在可选项中
+func validateParamValue(param *model.ParamInfo, value string) error {
+	if param.Type == placeholder.TypeEnum && len(param.Options) > 0 {
+		if !slices.Contains(param.Options, value) {
+			return fmt.Errorf(i18n.Dtr("paramEnumInvalid"), strings.Join(param.Options, ", "))
+		}
+		return nil
+	}
+	return validate.ValidateParam(value, param.Type)
+}
+
+// fillPresetParam 使用预先指定的值填充参数，没有预先指定或校验失败时返回 false，校验失败时同时返回错误
+func fillPresetParam(param *model.ParamInfo) (bool, error) {
+	value, ok := presetValue(param.Param)
+	if !ok {
+		return false, nil
+	}
+	if err := validateParamValue(param, value); err != nil {
+		return false, fmt.Errorf(i18n.Dtr("paramPresetInvalid"), param.Param, err)
+	}
+	if param.Type == placeholder.TypeSecret {
+		logger.AddSecret(value)
+	}
+	param.Value = value
+	return true, nil
+}
+
+// FillParams 不经交互补充参数并返回替换参数后的脚本和需要传给脚本的环境变量。
+// 依次使用 --param、WENAI_PARAM_<NAME> 和占位中的默认值，secret 类型参数不使用默认值，
+// 仍有参数缺少值或校验失败时返回错误
+func FillParams(hiddenParams *model.HiddenParams) (string, []string, error) {
+	var missing []string
+	for i := range hiddenParams.NeedFillParams {
+		param := &hiddenParams.NeedFillParams[i]
+		filled, err := fillPresetParam(param)
+		if err != nil {
+			return "", nil, err
+		}
+		if filled {
+			continue
+		}
+		if param.Default != "" && param.Type != placeholder.TypeSecret {
+			if err := validateParamValue(param, param.Default); err != nil {
+				return "", nil, fmt.Errorf(i18n.Dtr("paramPresetInvalid"), param.Param, err)
+			}
+			param.Value = param.Default
+			continue
+		}
+		missing = append(missing, fmt.Sprintf("%s (--param %q / %s)", param.Param, param.Param+"=…", ParamEnvName(param.Param)))
+	}
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf(i18n.Dtr("paramsMissing"), "\n  "+strings.Join(missing, "\n  "))
+	}
+
+	shellCode, env, err := replaceParams(hiddenParams.ShellCode, hiddenParams.NeedFillParams)
+	if err != nil {
+		return "", nil, err
+	}
+	logger.Debugf(i18n.Dtr("scriptToExecute"), shellCode)
+	printFinalScript(shellCode)
+	return shellCode, env, nil
+}
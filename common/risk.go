@@ -0,0 +1,99 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/risk"
+	"wen-ai-cli/setup"
+
+	"github.com/gookit/i18n"
+	"github.com/manifoldco/promptui"
+)
+
+// riskConfirmWord 运行严重危险的脚本前需要输入的确认词
+const riskConfirmWord = "confirm"
+
+// ConfirmRisk 分析脚本中的危险命令，展示红色警告并按最高危险等级确认：提醒只展示警告，
+// 高危需要再次确认，严重需要输入确认词；关闭交互时拒绝运行高于 --allow-risk 等级（默认为提醒）的脚本。返回是否继续运行
+func ConfirmRisk(shellCode string) bool {
+	severity := ShowRisk(shellCode)
+	if severity == 0 {
+		return true
+	}
+	if setup.IsNonInteractive() {
+		// 参数已在启动时校验
+		allowed, _ := risk.ParseSeverity(setup.GetAllowRisk())
+		if severity > allowed {
+			logger.Errorf(i18n.Dtr("riskRefusedAllow"), severity, severity.Name())
+			return false
+		}
+		return true
+	}
+	var confirmed bool
+	var err error
+	switch severity {
+	case risk.SeverityCritical:
+		confirmed, err = confirmRiskWord()
+	case risk.SeverityHigh:
+		confirmed, err = confirmRiskSelect()
+	default:
+		return true
+	}
+	if err != nil {
+		logger.Errorf("Prompt failed %v", err)
+		return false
+	}
+	if !confirmed {
+		logger.Info(i18n.Dtr("riskCanceled"))
+	}
+	return confirmed
+}
+
+// ShowRisk 分析脚本中的危险命令并以红色警告框展示，返回最高危险等级，没有危险命令时返回 0
+func ShowRisk(shellCode string) risk.Severity {
+	findings := risk.Analyze(shellCode)
+	if len(findings) == 0 {
+		return 0
+	}
+	printRiskWarning(findings)
+	return risk.Highest(findings)
+}
+
+// printRiskWarning 以红色警告框展示命中的危险命令
+func printRiskWarning(findings []risk.Finding) {
+	printer := NewWarningStreamPrinter(i18n.Dtr("riskTitle"), risk.Highest(findings).String())
+	for _, finding := range findings {
+		logger.Debugf("risk rule %s matched: %s", finding.RuleID, finding.Command)
+		printer.Print(fmt.Sprintf("[%s] %s\n", finding.Severity, finding.Message))
+		printer.Print("  $ " + finding.Command + "\n")
+	}
+	printer.Flush()
+}
+
+// confirmRiskSelect 再次确认是否运行高危脚本，默认选中否
+func confirmRiskSelect() (bool, error) {
+	confirm := promptui.Select{
+		HideHelp: true,
+		Label:    i18n.Dtr("riskConfirmHigh"),
+		Items:    []string{i18n.Dtr("no"), i18n.Dtr("yes")},
+	}
+	_, result, err := confirm.Run()
+	if err != nil {
+		return false, err
+	}
+	return result == i18n.Dtr("yes"), nil
+}
+
+// confirmRiskWord 要求输入确认词后才运行严重危险的脚本，输入其他内容时取消运行
+func confirmRiskWord() (bool, error) {
+	prompt := promptui.Prompt{
+		Label:       fmt.Sprintf(i18n.Dtr("riskConfirmCritical"), riskConfirmWord),
+		HideEntered: true,
+	}
+	result, err := prompt.Run()
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(result) == riskConfirmWord, nil
+}
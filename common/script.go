@@ -2,18 +2,25 @@ package common
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"wen-ai-cli/logger"
 	"wen-ai-cli/model"
+	"wen-ai-cli/paramhistory"
+	"wen-ai-cli/placeholder"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/shell"
 	"wen-ai-cli/validate"
 
 	"github.com/gookit/i18n"
 	"github.com/manifoldco/promptui"
 )
 
-// HandleScriptAdjustment 处理脚本微调运行逻辑
-func HandleScriptAdjustment(defaultScript string) (string, bool) {
+// HandleScriptAdjustment 处理脚本微调运行逻辑，返回最终脚本和需要传给脚本的环境变量。
+// 微调后的脚本中保留的参数占位按补充参数的流程填写，secret 类型参数同样以环境变量传入，不会写入脚本和日志
+func HandleScriptAdjustment(hiddenParams *model.HiddenParams) (string, []string, bool) {
 	validateFn := func(input string) error {
 		if len(input) < 1 {
 			return errors.New(i18n.Dtr("paramEmptyError"))
@@ -24,18 +31,23 @@ func HandleScriptAdjustment(defaultScript string) (string, bool) {
 	prompt := promptui.Prompt{
 		Label:    ">",
 		Validate: validateFn,
-		Default:  defaultScript,
+		Default:  hiddenParams.ShellCode,
 	}
 
 	result, err := prompt.Run()
 
 	if err != nil {
 		logger.Errorf("Prompt failed %v", err)
-		return "", false
+		return "", nil, false
 	}
 
 	logger.Debugf(i18n.Dtr("adjustedScript"), slog.String("script", result))
 
+	if params := placeholder.Parse(result); len(params) > 0 {
+		mergeParamInfo(params, hiddenParams.NeedFillParams)
+		return HandleParamsCompletion(&model.HiddenParams{ShellCode: result, NeedFillParams: params})
+	}
+
 	// 要求用户确认是否运行
 	confirm := promptui.Select{
 		HideHelp: true,
@@ -45,10 +57,32 @@ func HandleScriptAdjustment(defaultScript string) (string, bool) {
 	_, confirmResult, err := confirm.Run()
 	if err != nil {
 		logger.Errorf("Prompt failed %v", err)
-		return "", false
+		return "", nil, false
 	}
 
-	return result, confirmResult == i18n.Dtr("yes")
+	return result, nil, confirmResult == i18n.Dtr("yes")
+}
+
+// mergeParamInfo 微调后仍保留的占位沿用原参数的说明、默认值和选项，例如 propose_command 工具给出的补充信息
+func mergeParamInfo(params []model.ParamInfo, original []model.ParamInfo) {
+	for i := range params {
+		param := &params[i]
+		index := slices.IndexFunc(original, func(p model.ParamInfo) bool {
+			return placeholder.Raw(p) == param.Placeholder
+		})
+		if index < 0 {
+			continue
+		}
+		if param.Description == "" {
+			param.Description = original[index].Description
+		}
+		if param.Default == "" {
+			param.Default = original[index].Default
+		}
+		if len(param.Options) == 0 {
+			param.Options = original[index].Options
+		}
+	}
 }
 
 // ConfirmExecution 确认是否执行脚本
@@ -67,54 +101,181 @@ func ConfirmExecution() (bool, error) {
 	return confirmResult == i18n.Dtr("yes"), nil
 }
 
-// HandleParamsCompletion 处理参数补全运行逻辑
-func HandleParamsCompletion(hiddenParams *model.HiddenParams) (string, bool) {
+// HandleParamsCompletion 处理参数补全运行逻辑，返回替换参数后的脚本和需要传给脚本的环境变量。
+// 通过 --param 或 WENAI_PARAM_<NAME> 预先指定了有效值的参数不再提示；
+// secret 类型参数的值不写入脚本，而是以环境变量引用替换占位，值通过环境变量传入
+func HandleParamsCompletion(hiddenParams *model.HiddenParams) (string, []string, bool) {
 	// 遍历参数获取用户输入
 	for i := range hiddenParams.NeedFillParams {
 		// 使用指针引用，确保修改能保存到原始数据
 		param := &hiddenParams.NeedFillParams[i]
-		// 获取参数类型
-		param_type := param.Type
-		// 获取参数名称
-		param_name := param.Param
-		// 判断参数类型，做不同校验规则
-		validateFn := func(input string) error {
-			return validate.ValidateParam(input, param_type)
+		// 通过 --param 或环境变量预先指定了有效值时不再提示
+		filled, err := fillPresetParam(param)
+		if err != nil {
+			logger.Warn(err.Error())
 		}
-
-		prompt := promptui.Prompt{
-			Label:       param_name,
-			Validate:    validateFn,
-			HideEntered: true,
+		if filled {
+			continue
 		}
-		result, err := prompt.Run()
-
+		result, err := promptParam(param)
 		if err != nil {
 			logger.Errorf("Prompt failed %v", err)
-			return "", false
+			return "", nil, false
+		}
+		if param.Type == placeholder.TypeSecret {
+			logger.AddSecret(result)
 		}
 		// 回填参数值
 		param.Value = result
 	}
 
 	// 替换参数
-	shell_code := hiddenParams.ShellCode
-	for _, param := range hiddenParams.NeedFillParams {
-		// 构建可能的参数形式：原始参数名和<>包裹的参数名
-		paramName := param.Param
-		paramType := param.Type
-		bracketParamName := "<" + paramName + "," + paramType + ">"
-		// 替换参数
-		shell_code = strings.Replace(shell_code, bracketParamName, param.Value, -1)
+	shell_code, env, err := replaceParams(hiddenParams.ShellCode, hiddenParams.NeedFillParams)
+	if err != nil {
+		logger.Error(err.Error())
+		return "", nil, false
 	}
 
-	// 打印最终脚本
+	// 打印最终脚本，参数值已按 shell 规则加上引号，确认前展示给用户
 	logger.Debugf(i18n.Dtr("scriptToExecute"), shell_code)
+	printFinalScript(shell_code)
 
+	// 试运行时不会执行脚本，不需要确认，也不记住参数值
+	if setup.IsDryRun() {
+		return shell_code, env, true
+	}
 	shouldExecute, err := ConfirmExecution()
 	if err != nil {
-		return "", false
+		return "", nil, false
+	}
+	// 只记住确认执行的参数值
+	if shouldExecute {
+		paramhistory.Remember(hiddenParams.NeedFillParams)
+	}
+
+	return shell_code, env, shouldExecute
+}
+
+// printFinalScript 展示替换参数后的最终脚本
+func printFinalScript(shellCode string) {
+	printer := NewStreamPrinterWithAllOptions(false, true, i18n.Dtr("finalScript"), setup.CliVersion)
+	printer.Print(shellCode + "\n")
+	printer.Flush()
+}
+
+// replaceParams 替换脚本中的参数占位，参数值按执行脚本的 shell 和占位所在的引号环境转义，
+// secret 类型参数替换为环境变量引用并返回对应的 KEY=value；参数无法安全替换到 heredoc 中时返回错误
+func replaceParams(script string, params []model.ParamInfo) (string, []string, error) {
+	dialect := shell.Current().Dialect
+	var env []string
+	envNames := map[int]string{}
+	for i, param := range params {
+		if param.Type == placeholder.TypeSecret {
+			name := fmt.Sprintf("%s%d", secretEnvPrefix, len(env)+1)
+			env = append(env, name+"="+param.Value)
+			envNames[i] = name
+		}
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, occurrence := range placeholder.Find(script, params) {
+		builder.WriteString(script[last:occurrence.Start])
+		last = occurrence.End
+		// 根据已替换的内容判断引号环境，已替换的值都是闭合的，不会影响判断
+		ctx := dialect.ContextAt(builder.String())
+		var value string
+		var err error
+		if name, ok := envNames[occurrence.Param]; ok {
+			value, err = dialect.EnvRef(name, ctx)
+		} else {
+			value, err = dialect.Quote(params[occurrence.Param].Value, ctx)
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf(i18n.Dtr("paramReplaceFailed"), params[occurrence.Param].Param, err)
+		}
+		builder.WriteString(value)
+	}
+	builder.WriteString(script[last:])
+	return builder.String(), env, nil
+}
+
+// secretEnvPrefix secret 类型参数的环境变量名前缀，按出现顺序加上序号
+const secretEnvPrefix = "WENAI_SECRET_"
+
+// promptParam 获取单个参数的值，enum 类型使用选择列表；其他类型有历史值时先从历史值中选择，
+// 否则使用输入框并预先填入默认值
+func promptParam(param *model.ParamInfo) (string, error) {
+	// 带有说明时在参数名称后展示
+	label := param.Param
+	if param.Description != "" {
+		label = param.Param + " (" + param.Description + ")"
+	}
+
+	// 敏感值掩码输入，不使用模型给出的默认值，也不读取历史值
+	if param.Type == placeholder.TypeSecret {
+		return promptInput(label, param.Type, "", '*')
+	}
+
+	recent := paramhistory.Recent(param.Param)
+	if param.Type == placeholder.TypeEnum && len(param.Options) > 0 {
+		cursor := max(slices.Index(param.Options, param.Default), 0)
+		// 最近使用过的选项优先于默认值
+		for _, value := range recent {
+			if index := slices.Index(param.Options, value); index >= 0 {
+				cursor = index
+				break
+			}
+		}
+		selector := promptui.Select{
+			Label:     label,
+			Items:     param.Options,
+			CursorPos: cursor,
+			HideHelp:  true,
+		}
+		_, result, err := selector.Run()
+		return result, err
+	}
+
+	// 历史值中跳过已不满足当前类型的值，例如已被删除的文件
+	var candidates []string
+	for _, value := range recent {
+		if validate.ValidateParam(value, param.Type) == nil {
+			candidates = append(candidates, value)
+		}
 	}
+	if len(candidates) > 0 {
+		if param.Default != "" && !slices.Contains(candidates, param.Default) {
+			candidates = append(candidates, param.Default)
+		}
+		selector := promptui.Select{
+			Label:    label,
+			Items:    append(candidates, i18n.Dtr("paramOtherValue")),
+			HideHelp: true,
+		}
+		index, result, err := selector.Run()
+		if err != nil {
+			return "", err
+		}
+		if index < len(candidates) {
+			return result, nil
+		}
+	}
+	return promptInput(label, param.Type, param.Default, 0)
+}
 
-	return shell_code, shouldExecute
+// promptInput 使用输入框获取参数值，按参数类型校验，mask 不为 0 时掩码显示
+func promptInput(label, paramType, defaultValue string, mask rune) (string, error) {
+	// 判断参数类型，做不同校验规则
+	validateFn := func(input string) error {
+		return validate.ValidateParam(input, paramType)
+	}
+	prompt := promptui.Prompt{
+		Label:       label,
+		Validate:    validateFn,
+		HideEntered: true,
+		Default:     defaultValue,
+		Mask:        mask,
+	}
+	return prompt.Run()
 }
@@ -21,12 +21,20 @@ type StreamPrinter struct {
 	printLineCount int  // 打印的总行数
 	hasPrinted     bool // 是否已经打印过内容
 
+	// 思考内容
+	reasoningStarted bool // 是否打印过思考内容
+	reasoningEnded   bool // 思考内容是否已结束
+	reasoningOpen    bool // 当前思考行是否已打印边框
+	reasoningColumn  int  // 当前思考行已占用的列数
+	reasoningRows    int  // 已打印的完整思考行数
+
 	// 内容颜色
 	titleColor       *color.Color // 标题颜色
 	listColor        *color.Color // 列表标记颜色
 	codeColor        *color.Color // 代码块边界颜色
 	codeContentColor *color.Color // 代码块内容颜色
 	normalColor      *color.Color // 普通文本颜色
+	reasoningColor   *color.Color // 思考内容颜色
 
 	// 边框字符
 	headerChar      string // 头部边框字符
@@ -65,6 +73,7 @@ func NewStreamPrinter() *StreamPrinter {
 		codeColor:        color.New(color.FgHiBlack), // 代码块边界使用灰色
 		codeContentColor: color.New(color.FgHiCyan),  // 代码块内容使用青色
 		normalColor:      color.New(color.Reset),     // 普通文本使用默认颜色
+		reasoningColor:   color.New(color.Faint),     // 思考内容使用暗色
 
 		// 边框字符
 		headerChar:      "╭──",
@@ -137,6 +146,21 @@ func NewStreamPrinterWithAllOptions(showHashTag bool, colorCode bool, headerText
 	return sp
 }
 
+// NewWarningStreamPrinter 创建一个红色边框、红色文字的警告打印器，用于展示危险命令等需要特别注意的内容
+func NewWarningStreamPrinter(headerText string, footerText string) *StreamPrinter {
+	sp := NewStreamPrinterWithTextOptions(headerText, footerText)
+	sp.colorCode = false
+	sp.normalColor = color.New(color.FgHiRed)
+	sp.listColor = color.New(color.FgRed, color.Bold)
+	sp.headerColor = color.New(color.FgRed)
+	sp.normalLineColor = color.New(color.FgRed)
+	sp.headingLineColor = color.New(color.FgRed)
+	sp.footerColor = color.New(color.FgRed)
+	sp.headerTextColor = color.New(color.FgHiRed, color.Bold)
+	sp.footerTextColor = color.New(color.FgRed)
+	return sp
+}
+
 // NewStreamPrinterWithColors 创建一个自定义颜色的流式打印器
 func NewStreamPrinterWithColors(titleAttr, listAttr, codeAttr, codeContentAttr, normalAttr color.Attribute) *StreamPrinter {
 	sp := NewStreamPrinter()
@@ -252,6 +276,88 @@ func (sp *StreamPrinter) Flush() {
 	sp.printFooter()
 }
 
+// PrintReasoning 以暗色打印模型的思考内容，思考内容位于正文之前，
+// 不等待换行直接输出，超出终端宽度时自动折行以便之后折叠
+func (sp *StreamPrinter) PrintReasoning(text string) {
+	if sp.reasoningEnded {
+		return
+	}
+	if sp.firstPrint {
+		sp.printHeader()
+	}
+	sp.reasoningStarted = true
+
+	width := 0
+	if isTerminal() {
+		width = terminalWidth() - 2 // 减去边框和空格
+	}
+	segment := strings.Builder{}
+	flushSegment := func() {
+		sp.reasoningColor.Print(segment.String())
+		segment.Reset()
+	}
+	for _, r := range text {
+		if r == '\r' {
+			continue
+		}
+		if !sp.reasoningOpen {
+			sp.normalLineColor.Print(sp.normalLineChar)
+			fmt.Print(" ")
+			sp.reasoningOpen = true
+			sp.reasoningColumn = 0
+		}
+		if r == '\n' {
+			flushSegment()
+			sp.endReasoningRow()
+			continue
+		}
+		w := runeWidth(r)
+		if width > 0 && sp.reasoningColumn+w > width {
+			flushSegment()
+			sp.endReasoningRow()
+			sp.normalLineColor.Print(sp.normalLineChar)
+			fmt.Print(" ")
+			sp.reasoningOpen = true
+		}
+		segment.WriteRune(r)
+		sp.reasoningColumn += w
+	}
+	flushSegment()
+}
+
+// EndReasoning 结束思考内容，collapse 为 true 时将已打印的思考内容折叠为一行摘要
+func (sp *StreamPrinter) EndReasoning(summary string, collapse bool) {
+	if !sp.reasoningStarted || sp.reasoningEnded {
+		return
+	}
+	sp.reasoningEnded = true
+	if sp.reasoningOpen {
+		sp.endReasoningRow()
+	}
+	if !collapse {
+		return
+	}
+	// 只有思考内容仍完整显示在屏幕内时才能回到起始行清除
+	if isTerminal() && sp.reasoningRows < terminalHeight() {
+		fmt.Printf("\033[%dA\033[J", sp.reasoningRows)
+		sp.printLineCount -= sp.reasoningRows
+	}
+	sp.normalLineColor.Print(sp.normalLineChar)
+	fmt.Print(" ")
+	sp.reasoningColor.Print(summary)
+	fmt.Println()
+	sp.printLineCount++
+}
+
+// endReasoningRow 结束当前思考行
+func (sp *StreamPrinter) endReasoningRow() {
+	fmt.Println()
+	sp.reasoningOpen = false
+	sp.reasoningColumn = 0
+	sp.reasoningRows++
+	sp.printLineCount++
+}
+
 func (sp *StreamPrinter) Clear() {
 	sp.clearOrClearAndPrint(false)
 }
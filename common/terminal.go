@@ -0,0 +1,45 @@
+package common
+
+import (
+	"os"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// isTerminal 标准输出是否为终端，输出被重定向时不做折行和光标移动
+func isTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// terminalWidth 获取终端宽度，获取失败时返回 80
+func terminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 {
+		return 80
+	}
+	return width
+}
+
+// terminalHeight 获取终端高度，获取失败时返回 24
+func terminalHeight() int {
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || height <= 0 {
+		return 24
+	}
+	return height
+}
+
+// runeWidth 估算字符在终端中占用的列数，中日韩文字和全角符号占两列
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20:
+		return 0
+	case unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana):
+		return 2
+	case r >= 0x3000 && r <= 0x303F, r >= 0xFF00 && r <= 0xFF60, r >= 0xFFE0 && r <= 0xFFE6:
+		return 2
+	default:
+		return 1
+	}
+}
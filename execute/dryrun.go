@@ -0,0 +1,42 @@
+package execute
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"wen-ai-cli/common"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/shell"
+
+	"github.com/gookit/i18n"
+)
+
+// PrintDryRun 展示将要执行的脚本、shell 和参数、工作目录、额外的环境变量以及语法检查结果，不执行脚本。
+// env 中的值来自 secret 类型参数，只展示变量名
+func PrintDryRun(shellCode string, env []string, syntaxErr error) {
+	target := shell.Current()
+	workDir, err := os.Getwd()
+	if err != nil {
+		workDir = err.Error()
+	}
+
+	printer := common.NewStreamPrinterWithAllOptions(false, true, i18n.Dtr("dryRunTitle"), setup.CliVersion)
+	printer.Print(fmt.Sprintf(i18n.Dtr("dryRunShell"), target.Name+" "+target.Arg+" <script>") + "\n")
+	printer.Print(fmt.Sprintf(i18n.Dtr("dryRunWorkDir"), workDir) + "\n")
+	if len(env) == 0 {
+		printer.Print(i18n.Dtr("dryRunEnvInherit") + "\n")
+	} else {
+		printer.Print(i18n.Dtr("dryRunEnvExtra") + "\n")
+		for _, entry := range env {
+			name, _, _ := strings.Cut(entry, "=")
+			printer.Print("  " + name + "=******\n")
+		}
+	}
+	if syntaxErr != nil {
+		printer.Print(fmt.Sprintf(i18n.Dtr("dryRunSyntax"), syntaxErr) + "\n")
+	} else {
+		printer.Print(fmt.Sprintf(i18n.Dtr("dryRunSyntax"), i18n.Dtr("syntaxOK")) + "\n")
+	}
+	printer.Print("```\n" + shellCode + "\n```\n")
+	printer.Flush()
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"time"
 	"wen-ai-cli/logger"
+	"wen-ai-cli/shell"
 
 	"github.com/go-cmd/cmd"
 	"github.com/gookit/i18n"
@@ -14,6 +15,7 @@ type ExecuteOptions struct {
 	ShowOutput  bool          // 是否显示输出
 	Timeout     time.Duration // 执行超时时间
 	RefreshRate time.Duration // 输出刷新频率
+	Env         []string      // 追加到当前环境变量之后的 KEY=value，用于传递 secret 类型参数
 }
 
 // DefaultOptions 默认执行选项
@@ -35,15 +37,10 @@ func printCommandStatus(status cmd.Status) {
 	}
 }
 
-// 获取系统对应的Shell
+// 获取执行脚本的Shell
 func getSystemShell() (string, string) {
-	shellName := "bash"
-	shellArg := "-c"
-	if os.PathSeparator == '\\' { // Windows
-		shellName = "powershell"
-		shellArg = "-Command"
-	}
-	return shellName, shellArg
+	target := shell.Current()
+	return target.Name, target.Arg
 }
 
 // ExecuteScriptWithOptions 使用指定选项执行shell脚本
@@ -53,6 +50,9 @@ func ExecuteScriptWithOptions(shellCode string, options ExecuteOptions) (int, er
 
 	// 创建cmd实例
 	command := cmd.NewCmd(shellName, shellArg, shellCode)
+	if len(options.Env) > 0 {
+		command.Env = append(os.Environ(), options.Env...)
+	}
 
 	// 配置超时
 	if options.Timeout > 0 {
@@ -101,13 +101,16 @@ func ExecuteScriptWithOptions(shellCode string, options ExecuteOptions) (int, er
 	return finalStatus.Exit, nil
 }
 
-// ExecuteScript 使用默认选项执行shell脚本
-func ExecuteScript(shellCode string) {
+// ExecuteScript 使用默认选项执行shell脚本并返回退出码，env 为额外的环境变量，例如 secret 类型参数的值
+func ExecuteScript(shellCode string, env ...string) int {
 	logger.Debugf(i18n.Dtr("executingScript"), shellCode)
-	exitCode, _ := ExecuteScriptWithOptions(shellCode, DefaultOptions())
+	options := DefaultOptions()
+	options.Env = env
+	exitCode, _ := ExecuteScriptWithOptions(shellCode, options)
 
 	// 如果退出码不为0，可以记录日志等操作
 	if exitCode != 0 {
 		logger.Warnf("警告：脚本执行异常，退出码: %d", exitCode)
 	}
+	return exitCode
 }
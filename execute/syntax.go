@@ -0,0 +1,56 @@
+package execute
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/shell"
+)
+
+// SyntaxError 脚本语法检查失败
+type SyntaxError struct {
+	Shell  string // 检查语法使用的 shell
+	Output string // shell 输出的错误信息
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("%s: syntax error", e.Shell)
+	}
+	return e.Output
+}
+
+// syntaxCheckArgs 获取 shell 只检查语法而不执行脚本的参数，不支持时返回 false
+func syntaxCheckArgs(target shell.Target) ([]string, bool) {
+	switch target.Dialect {
+	case shell.POSIX:
+		return []string{"-n", target.Arg}, true
+	case shell.Fish:
+		return []string{"--no-execute", target.Arg}, true
+	default:
+		// PowerShell 没有只检查语法的启动参数
+		return nil, false
+	}
+}
+
+// CheckSyntax 使用执行脚本的 shell 检查脚本语法而不执行，例如 bash -n -c。
+// shell 不支持语法检查或无法启动时跳过检查，返回 nil
+func CheckSyntax(shellCode string) error {
+	target := shell.Current()
+	args, ok := syntaxCheckArgs(target)
+	if !ok {
+		return nil
+	}
+	output, err := exec.Command(target.Name, append(args, shellCode)...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		logger.Debugf("syntax check skipped: %v", err)
+		return nil
+	}
+	return &SyntaxError{Shell: target.Name, Output: strings.TrimSpace(string(output))}
+}
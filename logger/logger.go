@@ -153,7 +153,7 @@ func log(ctx context.Context, level slog.Level, msg string, args ...any) {
 		initLogger()
 	}
 	if logger != nil {
-		logger.Log(ctx, level, msg, args...)
+		logger.Log(ctx, level, Redact(msg), redactArgs(args)...)
 	}
 }
 
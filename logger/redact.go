@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// redactedText 替换敏感值的文本
+const redactedText = "******"
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// AddSecret 登记本次运行中的敏感值，之后的日志输出和 Redact 都会将其替换为 ******
+func AddSecret(value string) {
+	if value == "" {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = append(secrets, value)
+}
+
+// Redact 将文本中已登记的敏感值替换为 ******
+func Redact(text string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, redactedText)
+	}
+	return text
+}
+
+// redactArgs 替换日志参数中字符串类型的敏感值
+func redactArgs(args []any) []any {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			args[i] = Redact(v)
+		case slog.Attr:
+			if v.Value.Kind() == slog.KindString {
+				args[i] = slog.String(v.Key, Redact(v.Value.String()))
+			}
+		}
+	}
+	return args
+}
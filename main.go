@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"wen-ai-cli/action"
 	"wen-ai-cli/cmd"
 	"wen-ai-cli/logger"
+	"wen-ai-cli/model"
+	"wen-ai-cli/risk"
 	"wen-ai-cli/setup"
 
 	"github.com/gookit/i18n"
@@ -22,17 +25,86 @@ func main() {
 		Name:   "wen",
 		Usage:  i18n.Dtr("usage"),
 		Action: action.NewWenOnceAction(),
+		// --param 的值中可能包含逗号，不按逗号拆分
+		DisableSliceFlagSeparator: true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "profile",
+				Aliases: []string{"p"},
+				Value:   "",
+				Usage:   i18n.Dtr("profileFlag"),
+			},
+			&cli.BoolFlag{
+				Name:  "no-cache",
+				Usage: i18n.Dtr("noCacheFlag"),
+			},
+			&cli.FloatFlag{
+				Name:  "temperature",
+				Usage: i18n.Dtr("temperatureFlag"),
+			},
+			&cli.IntFlag{
+				Name:  "max-tokens",
+				Usage: i18n.Dtr("maxTokensFlag"),
+			},
+			&cli.StringSliceFlag{
+				Name:  "param",
+				Usage: i18n.Dtr("paramFlag"),
+			},
+			&cli.BoolFlag{
+				Name:    "non-interactive",
+				Usage:   i18n.Dtr("nonInteractiveFlag"),
+				Sources: cli.EnvVars("WENAI_NON_INTERACTIVE"),
+			},
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				Usage:   i18n.Dtr("dryRunFlag"),
+				Sources: cli.EnvVars("WENAI_DRY_RUN"),
+			},
+			&cli.StringFlag{
+				Name:    "allow-risk",
+				Usage:   i18n.Dtr("allowRiskFlag"),
+				Sources: cli.EnvVars("WENAI_ALLOW_RISK"),
+			},
+			// 录制与回放 cassette，用于离线演示和测试，不在帮助中展示
+			&cli.StringFlag{
+				Name:    "record",
+				Hidden:  true,
+				Sources: cli.EnvVars("WENAI_RECORD"),
+			},
+			&cli.StringFlag{
+				Name:    "replay",
+				Hidden:  true,
+				Sources: cli.EnvVars("WENAI_REPLAY"),
+			},
+		},
 		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			// 设置本次运行使用的配置档
+			setup.SetProfileOverride(cmd.String("profile"))
+			setup.SetCacheDisabled(cmd.Bool("no-cache"))
+			setup.SetCassette(cmd.String("record"), cmd.String("replay"))
+			setup.SetGenerationOverride(generationFlags(cmd))
+			setup.SetNonInteractive(cmd.Bool("non-interactive"))
+			setup.SetDryRun(cmd.Bool("dry-run"))
+			if err := setup.SetParamOverrides(cmd.StringSlice("param")); err != nil {
+				return nil, cli.Exit(err.Error(), 2)
+			}
+			if _, err := risk.ParseSeverity(cmd.String("allow-risk")); err != nil {
+				return nil, cli.Exit(fmt.Sprintf(i18n.Dtr("allowRiskInvalid"), cmd.String("allow-risk")), 2)
+			}
+			setup.SetAllowRisk(cmd.String("allow-risk"))
 			// 获取当前要运行的command
 			command := cmd.Args().First()
-			// 如果command是config，则不检查必要配置
-			if command == setup.ConfigCmd || command == setup.ConfigCmdAlias {
+			// 如果command是config、usage、cache或models，则不检查必要配置
+			if command == setup.ConfigCmd || command == setup.ConfigCmdAlias || command == setup.UsageCmd || command == setup.CacheCmd || command == setup.ModelsCmd {
+				return ctx, nil
+			}
+			// 回放 cassette 时不连接大模型服务，无需检查配置档
+			if setup.GetReplayCassette() != "" {
 				return ctx, nil
 			}
-			// 检查必要配置
-			config := setup.GetConfig()
-			if config.OpenAI.APIKey == "" || config.OpenAI.BaseURL == "" || config.OpenAI.Model == "" {
-				return nil, cli.Exit(i18n.Dtr("configError"), 400)
+			// 检查所选配置档的必要配置
+			if err := setup.ValidateActiveProfile(); err != nil {
+				return nil, cli.Exit(err.Error(), 2)
 			}
 			return ctx, nil
 		},
@@ -40,6 +112,9 @@ func main() {
 			cmd.NewChatCmd(),
 			cmd.NewConfigCmd(),
 			cmd.NewManualCmd(),
+			cmd.NewUsageCmd(),
+			cmd.NewCacheCmd(),
+			cmd.NewModelsCmd(),
 		},
 	}
 	// 运行命令
@@ -47,3 +122,17 @@ func main() {
 		logger.Fatal(err.Error())
 	}
 }
+
+// generationFlags 读取命令行中指定的生成参数，未指定的参数保持为空
+func generationFlags(cmd *cli.Command) model.GenerationOptions {
+	options := model.GenerationOptions{}
+	if cmd.IsSet("temperature") {
+		temperature := float32(cmd.Float("temperature"))
+		options.Temperature = &temperature
+	}
+	if cmd.IsSet("max-tokens") {
+		maxTokens := int(cmd.Int("max-tokens"))
+		options.MaxTokens = &maxTokens
+	}
+	return options
+}
@@ -0,0 +1,9 @@
+package model
+
+// Answer 按回答格式解析后的结构化回答
+type Answer struct {
+	Overview     string        `json:"overview"`     // 概述，没有概述标题时为第一个标题之前的内容
+	Analysis     string        `json:"analysis"`     // 脚本分析
+	CommonParams string        `json:"commonParams"` // 常用参数
+	Blocks       []ScriptBlock `json:"blocks"`       // 全部可运行的代码块，按出现顺序排列
+}
@@ -1,11 +1,119 @@
 package model
 
+// OpenAI 旧版单一模型配置，仅用于迁移到 Profiles
 type OpenAI struct {
 	APIKey  string `mapstructure:"apiKey" json:"apiKey"`
 	BaseURL string `mapstructure:"baseURL" json:"baseURL"`
 	Model   string `mapstructure:"model" json:"model"`
 }
 
+// 模型服务类型
+const (
+	ProviderOpenAI    = "openai"    // OpenAI 兼容接口
+	ProviderOllama    = "ollama"    // Ollama 原生接口
+	ProviderAnthropic = "anthropic" // Anthropic Messages API
+)
+
+// Providers 支持的模型服务类型
+var Providers = []string{ProviderOpenAI, ProviderOllama, ProviderAnthropic}
+
+// OllamaOptions Ollama 原生接口的专有选项
+type OllamaOptions struct {
+	KeepAlive string `mapstructure:"keepAlive" json:"keepAlive"` // 模型在内存中的保留时长，例如 5m、-1
+	NumCtx    int    `mapstructure:"numCtx" json:"numCtx"`       // 上下文窗口大小，0 表示使用模型默认值
+	AutoPull  bool   `mapstructure:"autoPull" json:"autoPull"`   // 模型不存在时是否自动拉取
+}
+
+// AnthropicOptions Anthropic Messages API 的专有选项
+type AnthropicOptions struct {
+	Version   string `mapstructure:"version" json:"version"`     // anthropic-version 请求头，为空时使用默认版本
+	MaxTokens int    `mapstructure:"maxTokens" json:"maxTokens"` // 最大输出 token 数，为空时使用默认值
+}
+
+// AzureOptions Azure OpenAI 部署的专有选项，仅对 openai 服务类型生效
+type AzureOptions struct {
+	Enabled    bool   `mapstructure:"enabled" json:"enabled"`       // 是否以 Azure 模式访问，使用 api-key 请求头和 api-version 参数
+	Deployment string `mapstructure:"deployment" json:"deployment"` // 部署名称，为空时由模型名称推导
+	APIVersion string `mapstructure:"apiVersion" json:"apiVersion"` // api-version 查询参数，为空时使用默认版本
+}
+
+// GenerationOptions 生成参数，未填写的字段使用服务端默认值
+type GenerationOptions struct {
+	Temperature *float32 `mapstructure:"temperature" json:"temperature,omitempty"` // 采样温度
+	MaxTokens   *int     `mapstructure:"maxTokens" json:"maxTokens,omitempty"`     // 最大输出 token 数
+	TopP        *float32 `mapstructure:"topP" json:"topP,omitempty"`               // 核采样概率
+	Stop        []string `mapstructure:"stop" json:"stop,omitempty"`               // 停止序列
+	Seed        *int     `mapstructure:"seed" json:"seed,omitempty"`               // 随机种子，anthropic 服务类型不支持
+}
+
+// Merge 用 override 中已填写的字段覆盖当前参数
+func (g GenerationOptions) Merge(override GenerationOptions) GenerationOptions {
+	if override.Temperature != nil {
+		g.Temperature = override.Temperature
+	}
+	if override.MaxTokens != nil {
+		g.MaxTokens = override.MaxTokens
+	}
+	if override.TopP != nil {
+		g.TopP = override.TopP
+	}
+	if len(override.Stop) > 0 {
+		g.Stop = override.Stop
+	}
+	if override.Seed != nil {
+		g.Seed = override.Seed
+	}
+	return g
+}
+
+// NetworkOptions 访问模型服务的网络选项，适用于所有服务类型
+type NetworkOptions struct {
+	Proxy              string            `mapstructure:"proxy" json:"proxy,omitempty"`                           // HTTP/HTTPS 代理地址，为空时使用 HTTP_PROXY 等环境变量
+	Headers            map[string]string `mapstructure:"headers" json:"headers,omitempty"`                       // 每个请求附加的请求头，例如租户标识
+	CAFile             string            `mapstructure:"caFile" json:"caFile,omitempty"`                         // 额外信任的 CA 证书文件（PEM），与系统证书一起使用
+	CertFile           string            `mapstructure:"certFile" json:"certFile,omitempty"`                     // 客户端证书文件（PEM），用于双向 TLS
+	KeyFile            string            `mapstructure:"keyFile" json:"keyFile,omitempty"`                       // 客户端证书私钥文件（PEM）
+	InsecureSkipVerify bool              `mapstructure:"insecureSkipVerify" json:"insecureSkipVerify,omitempty"` // 跳过服务端证书校验，仅用于调试
+}
+
+// Profile 命名的模型服务配置档
+type Profile struct {
+	Provider   string            `mapstructure:"provider" json:"provider"` // 服务类型，为空时为 openai
+	APIKey     string            `mapstructure:"apiKey" json:"apiKey"`
+	BaseURL    string            `mapstructure:"baseURL" json:"baseURL"`
+	Model      string            `mapstructure:"model" json:"model"`
+	Ollama     OllamaOptions     `mapstructure:"ollama" json:"ollama"`
+	Anthropic  AnthropicOptions  `mapstructure:"anthropic" json:"anthropic"`
+	Azure      AzureOptions      `mapstructure:"azure" json:"azure"`
+	Network    NetworkOptions    `mapstructure:"network" json:"network"`
+	Generation GenerationOptions `mapstructure:"generation" json:"generation"`
+}
+
+// GetProvider 获取服务类型，未配置时默认为 openai
+func (p *Profile) GetProvider() string {
+	if p.Provider == "" {
+		return ProviderOpenAI
+	}
+	return p.Provider
+}
+
+// GetModel 获取模型名称，Azure 模式下未配置模型时使用部署名称
+func (p *Profile) GetModel() string {
+	if p.Model == "" && p.Azure.Enabled {
+		return p.Azure.Deployment
+	}
+	return p.Model
+}
+
+// IsComplete 返回配置档的必要信息是否已填写
+func (p *Profile) IsComplete() bool {
+	if p.BaseURL == "" || p.GetModel() == "" {
+		return false
+	}
+	// 本地 Ollama 服务不需要 apiKey
+	return p.APIKey != "" || p.GetProvider() == ProviderOllama
+}
+
 type Console struct {
 	Enabled bool   `mapstructure:"enabled" json:"enabled"`
 	Color   bool   `mapstructure:"color" json:"color"`
@@ -31,11 +139,101 @@ type AnswerConfig struct {
 	EnableExtendParams       bool `mapstructure:"enableExtendParams" json:"enableExtendParams"`
 	EnablePlatformPerception bool `mapstructure:"enablePlatformPerception" json:"enablePlatformPerception"`
 	EnableWorkUserAndDir     bool `mapstructure:"enableWorkUserAndDir" json:"enableWorkUserAndDir"`
+	EnableToolCalling        bool `mapstructure:"enableToolCalling" json:"enableToolCalling"` // 通过 propose_command 工具调用提交脚本，服务不支持时回退为解析回答文本
+}
+
+// RetryConfig 大模型请求失败时的重试配置，仅对建立请求阶段生效
+type RetryConfig struct {
+	MaxRetries     int `mapstructure:"maxRetries" json:"maxRetries"`         // 最大重试次数，0 使用默认值，负数关闭重试
+	InitialDelayMs int `mapstructure:"initialDelayMs" json:"initialDelayMs"` // 首次重试等待毫秒数，之后按指数增长
+	MaxDelayMs     int `mapstructure:"maxDelayMs" json:"maxDelayMs"`         // 单次重试最长等待毫秒数
+}
+
+// ModelPrice 模型单价，单位为每百万 token 的价格
+type ModelPrice struct {
+	Prompt     float64 `mapstructure:"prompt" json:"prompt"`
+	Completion float64 `mapstructure:"completion" json:"completion"`
+}
+
+// UsageConfig token 用量统计配置
+type UsageConfig struct {
+	ShowInFooter bool                  `mapstructure:"showInFooter" json:"showInFooter"` // 是否在回答尾部展示 token 用量
+	Currency     string                `mapstructure:"currency" json:"currency"`         // 费用展示的货币符号
+	Prices       map[string]ModelPrice `mapstructure:"prices" json:"prices"`             // 按模型名称配置的单价
+}
+
+// CacheConfig 回答缓存配置
+type CacheConfig struct {
+	Enabled   *bool `mapstructure:"enabled" json:"enabled,omitempty"` // 是否启用回答缓存，未填写时启用
+	TTLHours  int   `mapstructure:"ttlHours" json:"ttlHours"`         // 缓存有效期（小时），0 表示永不过期
+	MaxSizeMB int   `mapstructure:"maxSizeMB" json:"maxSizeMB"`       // 缓存目录最大容量（MB），超出时淘汰最旧的缓存，0 表示不限制
+}
+
+// IsEnabled 返回是否启用回答缓存，旧版配置中没有该字段时默认启用
+func (c CacheConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// 思考内容展示方式
+const (
+	ReasoningCollapse = "collapse" // 实时展示，回答正文开始后折叠为一行摘要
+	ReasoningExpand   = "expand"   // 实时展示并保留
+	ReasoningHide     = "hide"     // 不展示
+)
+
+// ReasoningConfig 推理模型思考内容的展示配置
+type ReasoningConfig struct {
+	Display string `mapstructure:"display" json:"display"` // 展示方式：collapse、expand、hide，默认 collapse
+}
+
+// GetDisplay 获取思考内容展示方式，未填写或无效时使用 collapse
+func (r ReasoningConfig) GetDisplay() string {
+	switch r.Display {
+	case ReasoningExpand, ReasoningHide:
+		return r.Display
+	default:
+		return ReasoningCollapse
+	}
+}
+
+// Fallback 当前配置档请求失败时依次尝试的备用配置档
+type Fallback struct {
+	Profile string `mapstructure:"profile" json:"profile"`       // 配置档名称
+	Model   string `mapstructure:"model" json:"model,omitempty"` // 覆盖配置档中的模型，为空时使用配置档的模型
+}
+
+// ParamHistoryConfig 参数历史值配置，记住填写过的参数值作为下次的候选
+type ParamHistoryConfig struct {
+	Enabled      *bool `mapstructure:"enabled" json:"enabled,omitempty"` // 是否记住填写过的参数值，secret 类型参数始终不记录，未填写时启用
+	PerDirectory bool  `mapstructure:"perDirectory" json:"perDirectory"` // 是否按当前目录分别记录，当前目录没有记录时使用全局记录
+	MaxValues    int   `mapstructure:"maxValues" json:"maxValues"`       // 每个参数保留的最近值数量，0 使用默认值
+}
+
+// IsEnabled 返回是否记住填写过的参数值，旧版配置中没有该字段时默认启用
+func (c ParamHistoryConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// ParamType 用户自定义的参数类型，输入值需要完整匹配正则表达式
+type ParamType struct {
+	Pattern string `mapstructure:"pattern" json:"pattern"`           // 输入值需要完整匹配的正则表达式
+	Message string `mapstructure:"message" json:"message,omitempty"` // 不匹配时的提示，为空时使用默认提示
 }
 
 type Config struct {
-	DefaultLang  string       `mapstructure:"defaultLang" json:"defaultLang"`
-	OpenAI       OpenAI       `mapstructure:"openai" json:"openai"`
-	Logger       Logger       `mapstructure:"logger" json:"logger"`
-	AnswerConfig AnswerConfig `mapstructure:"answerConfig" json:"answerConfig"`
+	DefaultLang   string                       `mapstructure:"defaultLang" json:"defaultLang"`
+	OpenAI        *OpenAI                      `mapstructure:"openai" json:"openai,omitempty"` // 旧版配置，加载时迁移为 default 配置档
+	ActiveProfile string                       `mapstructure:"activeProfile" json:"activeProfile"`
+	Profiles      map[string]Profile           `mapstructure:"profiles" json:"profiles"`
+	Fallbacks     []Fallback                   `mapstructure:"fallbacks" json:"fallbacks,omitempty"`   // 遇到可重试错误时按顺序切换的备用配置档
+	Generation    map[string]GenerationOptions `mapstructure:"generation" json:"generation,omitempty"` // 按子命令（once、chat、man）覆盖配置档中的生成参数
+	Logger        Logger                       `mapstructure:"logger" json:"logger"`
+	AnswerConfig  AnswerConfig                 `mapstructure:"answerConfig" json:"answerConfig"`
+	Retry         RetryConfig                  `mapstructure:"retry" json:"retry"`
+	Usage         UsageConfig                  `mapstructure:"usage" json:"usage"`
+	Cache         CacheConfig                  `mapstructure:"cache" json:"cache"`
+	Reasoning     ReasoningConfig              `mapstructure:"reasoning" json:"reasoning"`
+	Shell         string                       `mapstructure:"shell" json:"shell,omitempty"` // 执行脚本使用的 shell：bash、sh、zsh、fish、powershell、pwsh，为空时 Windows 使用 powershell，其他系统使用 bash
+	ParamHistory  ParamHistoryConfig           `mapstructure:"paramHistory" json:"paramHistory"`
+	ParamTypes    map[string]ParamType         `mapstructure:"paramTypes" json:"paramTypes,omitempty"` // 用户自定义的参数类型，名称不区分大小写，不能覆盖内置类型
 }
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDefaultEnabled(t *testing.T) {
+	tests := []struct {
+		json string
+		want bool
+	}{
+		{`{}`, true},
+		{`{"enabled":true}`, true},
+		{`{"enabled":false}`, false},
+	}
+	for _, tt := range tests {
+		var cache CacheConfig
+		if err := json.Unmarshal([]byte(tt.json), &cache); err != nil {
+			t.Fatal(err)
+		}
+		if got := cache.IsEnabled(); got != tt.want {
+			t.Errorf("CacheConfig %s IsEnabled() = %v, want %v", tt.json, got, tt.want)
+		}
+		var history ParamHistoryConfig
+		if err := json.Unmarshal([]byte(tt.json), &history); err != nil {
+			t.Fatal(err)
+		}
+		if got := history.IsEnabled(); got != tt.want {
+			t.Errorf("ParamHistoryConfig %s IsEnabled() = %v, want %v", tt.json, got, tt.want)
+		}
+	}
+}
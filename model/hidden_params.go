@@ -2,19 +2,45 @@ package model
 
 // ParamInfo 表示需要填充的参数信息
 type ParamInfo struct {
-	Param string `json:"param"` // 参数字段名称
-	Type  string `json:"type"`  // 参数类型：string、url、number等
-	Value string `json:"value"` // 参数值，用户输入后回填
+	Param       string   `json:"param"`                 // 参数字段名称
+	Type        string   `json:"type"`                  // 参数类型：string、url、number等
+	Value       string   `json:"value"`                 // 参数值，用户输入后回填
+	Description string   `json:"description,omitempty"` // 参数说明
+	Default     string   `json:"default,omitempty"`     // 参数默认值，补充参数时预先填入
+	Options     []string `json:"options,omitempty"`     // enum 类型的可选值
+	Placeholder string   `json:"placeholder,omitempty"` // 参数在脚本中的完整占位文本，用于替换
+}
+
+// ScriptBlock 回答中的一个代码块及其参数
+type ScriptBlock struct {
+	Title          string      `json:"title"`              // 代码块所在的标题，没有标题时为空
+	Language       string      `json:"language,omitempty"` // 代码块标注的语言，例如 code、bash、powershell
+	ShellCode      string      `json:"shellCode"`          // 代码块内容
+	NeedFillParams []ParamInfo `json:"needFillParams"`     // 代码块中需要填充的参数
 }
 
 // HiddenParams 用于存储隐藏参数
 type HiddenParams struct {
-	NeedFillParams []ParamInfo `json:"needFillParams"` // 需要填充的参数列表
-	Raw            string      // 保存原始JSON字符串
-	ShellCode      string      `json:"shellCode"` // 保存代码片段
+	NeedFillParams []ParamInfo   `json:"needFillParams"` // 需要填充的参数列表
+	Raw            string        // 保存原始JSON字符串
+	ShellCode      string        `json:"shellCode"` // 保存代码片段
+	RiskNotes      []string      `json:"riskNotes"` // 执行脚本的风险提示，来自 propose_command 工具调用
+	Blocks         []ScriptBlock `json:"blocks"`    // 回答中的全部代码块，ShellCode 和 NeedFillParams 为选中的代码块，默认是最后一个
+	Answer         *Answer       `json:"answer"`    // 结构化的回答内容，流式读取中断时为 nil
 }
 
 // HasParameters 返回是否有需要填充的参数
 func (h *HiddenParams) HasParameters() bool {
 	return len(h.NeedFillParams) > 0
 }
+
+// SelectBlock 选中第 index 个代码块，ShellCode 和 NeedFillParams 替换为该代码块的内容
+func (h *HiddenParams) SelectBlock(index int) {
+	if index < 0 || index >= len(h.Blocks) {
+		return
+	}
+	block := h.Blocks[index]
+	h.ShellCode = block.ShellCode
+	// 复制参数，避免回填的参数值写回代码块
+	h.NeedFillParams = append([]ParamInfo(nil), block.NeedFillParams...)
+}
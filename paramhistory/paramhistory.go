@@ -0,0 +1,156 @@
+// Package paramhistory 记住用户填写过的参数值，按参数名称保存最近使用的值，
+// 可选按当前目录分别保存，secret 类型参数不会被记录
+package paramhistory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/model"
+	"wen-ai-cli/placeholder"
+	"wen-ai-cli/setup"
+)
+
+// DefaultMaxValues 每个参数默认保留的最近值数量
+const DefaultMaxValues = 10
+
+// store 参数历史值文件的内容，值按最近使用排在前面
+type store struct {
+	Global map[string][]string            `json:"global"`
+	Dirs   map[string]map[string][]string `json:"dirs,omitempty"`
+}
+
+// Recent 获取参数最近使用的值，最近的在前。按目录记录时当前目录的值在前，之后是全局记录
+func Recent(name string) []string {
+	conf := setup.GetConfig().ParamHistory
+	if !conf.IsEnabled() {
+		return nil
+	}
+	s, err := load()
+	if err != nil {
+		logger.Debugf("load param history failed: %v", err)
+		return nil
+	}
+	name = key(name)
+	var values []string
+	if conf.PerDirectory {
+		if dir, ok := currentDir(); ok {
+			values = append(values, s.Dirs[dir][name]...)
+		}
+	}
+	for _, value := range s.Global[name] {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Remember 记录本次填写的参数值，跳过 secret 类型参数和空值
+func Remember(params []model.ParamInfo) {
+	conf := setup.GetConfig().ParamHistory
+	if !conf.IsEnabled() {
+		return
+	}
+	maxValues := conf.MaxValues
+	if maxValues <= 0 {
+		maxValues = DefaultMaxValues
+	}
+	s, err := load()
+	if err != nil {
+		logger.Debugf("load param history failed: %v", err)
+		s = &store{}
+	}
+	if s.Global == nil {
+		s.Global = map[string][]string{}
+	}
+	dir, perDirectory := "", false
+	if conf.PerDirectory {
+		dir, perDirectory = currentDir()
+	}
+
+	changed := false
+	for _, param := range params {
+		if param.Type == placeholder.TypeSecret || param.Value == "" {
+			continue
+		}
+		name := key(param.Param)
+		s.Global[name] = pushFront(s.Global[name], param.Value, maxValues)
+		if perDirectory {
+			if s.Dirs == nil {
+				s.Dirs = map[string]map[string][]string{}
+			}
+			if s.Dirs[dir] == nil {
+				s.Dirs[dir] = map[string][]string{}
+			}
+			s.Dirs[dir][name] = pushFront(s.Dirs[dir][name], param.Value, maxValues)
+		}
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	if err := save(s); err != nil {
+		logger.Debugf("save param history failed: %v", err)
+	}
+}
+
+// key 参数名称去掉首尾空白后作为记录的键
+func key(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// pushFront 将值移动到最前面，并只保留最近的 maxValues 个
+func pushFront(values []string, value string, maxValues int) []string {
+	values = slices.DeleteFunc(values, func(v string) bool { return v == value })
+	values = append([]string{value}, values...)
+	if len(values) > maxValues {
+		values = values[:maxValues]
+	}
+	return values
+}
+
+// currentDir 获取当前目录的绝对路径
+func currentDir() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	return dir, true
+}
+
+// load 读取参数历史值文件，文件不存在时返回空记录
+func load() (*store, error) {
+	s := &store{}
+	data, err := os.ReadFile(setup.GetParamHistoryFilePath())
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// save 先写入临时文件再重命名，避免写入中断导致文件损坏
+func save(s *store) error {
+	path := setup.GetParamHistoryFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,102 @@
+// Package placeholder 解析和替换脚本中的参数占位，占位格式为：
+//
+//	<名称,类型[,默认值[,说明]]>
+//
+// 类型为 url、string、number、int:1..100 等已注册的参数类型，或 enum:选项1|选项2 表示只能从给定选项中选择，
+// 只有名称和类型的旧格式 <名称,类型> 同样支持
+package placeholder
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"wen-ai-cli/model"
+)
+
+const (
+	// TypeEnum 枚举类型，选项保存在 ParamInfo.Options 中
+	TypeEnum = "enum"
+	// TypeSecret 密码、令牌等敏感值，掩码输入并通过环境变量传给脚本
+	TypeSecret = "secret"
+)
+
+// pattern 匹配占位：名称不能以空白开头，可以包含空格、连字符和任意文字，类型为单词或 enum:选项列表，默认值和说明可选。
+// 各部分之间也可以使用全角逗号分隔。类型冒号之后的部分除 {2,5} 这样的花括号内以外不能包含逗号
+var pattern = regexp.MustCompile(`<([^<>,，\s][^<>,，\n]*?)\s*[,，]\s*(\w+(?::(?:[^<>,，\n{]|\{[^{}<>\n]*\})+)?)(?:\s*[,，]([^<>,，\n]*))?(?:[,，]([^<>\n]*))?>`)
+
+// Parse 解析脚本中的全部占位，同一占位出现多次时只返回一次
+func Parse(script string) []model.ParamInfo {
+	var params []model.ParamInfo
+	seen := map[string]bool{}
+	for _, match := range pattern.FindAllStringSubmatch(script, -1) {
+		raw := match[0]
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+
+		param := model.ParamInfo{
+			Param:       strings.TrimSpace(match[1]),
+			Type:        match[2],
+			Default:     strings.TrimSpace(match[3]),
+			Description: strings.TrimSpace(match[4]),
+			Placeholder: raw,
+		}
+		// 只有 enum 的冒号后是选项列表，其他类型的参数（例如 int:1..100）交给校验器处理
+		if typeName, options, ok := strings.Cut(param.Type, ":"); ok && strings.EqualFold(typeName, TypeEnum) {
+			param.Type = TypeEnum
+			for _, option := range strings.Split(options, "|") {
+				if option = strings.TrimSpace(option); option != "" {
+					param.Options = append(param.Options, option)
+				}
+			}
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// Occurrence 参数占位在脚本中的一次出现
+type Occurrence struct {
+	Start int // 占位起始位置
+	End   int // 占位结束位置（不含）
+	Param int // 参数在参数列表中的下标
+}
+
+// Find 查找全部参数占位在脚本中的出现位置，按位置排序，互相重叠时保留靠前的占位
+func Find(script string, params []model.ParamInfo) []Occurrence {
+	var occurrences []Occurrence
+	for i, param := range params {
+		raw := Raw(param)
+		for offset := 0; ; {
+			index := strings.Index(script[offset:], raw)
+			if index < 0 {
+				break
+			}
+			start := offset + index
+			occurrences = append(occurrences, Occurrence{Start: start, End: start + len(raw), Param: i})
+			offset = start + len(raw)
+		}
+	}
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Start < occurrences[j].Start
+	})
+	result := occurrences[:0]
+	end := 0
+	for _, occurrence := range occurrences {
+		if occurrence.Start < end {
+			continue
+		}
+		result = append(result, occurrence)
+		end = occurrence.End
+	}
+	return result
+}
+
+// Raw 获取参数在脚本中的占位文本，未记录时按 <名称,类型> 拼接
+func Raw(param model.ParamInfo) string {
+	if param.Placeholder != "" {
+		return param.Placeholder
+	}
+	return "<" + param.Param + "," + param.Type + ">"
+}
@@ -0,0 +1,64 @@
+package placeholder
+
+import (
+	"reflect"
+	"testing"
+	"wen-ai-cli/model"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []model.ParamInfo
+	}{
+		{
+			name:   "name type default description",
+			script: "curl -o <保存的文件名,string,a.txt,下载后保存的文件> <下载地址,url>",
+			want: []model.ParamInfo{
+				{Param: "保存的文件名", Type: "string", Default: "a.txt", Description: "下载后保存的文件", Placeholder: "<保存的文件名,string,a.txt,下载后保存的文件>"},
+				{Param: "下载地址", Type: "url", Placeholder: "<下载地址,url>"},
+			},
+		},
+		{
+			name:   "spaces and full-width commas",
+			script: "ping -c <ping count ， int:1..100，4> <target host，hostname>",
+			want: []model.ParamInfo{
+				{Param: "ping count", Type: "int:1..100", Default: "4", Placeholder: "<ping count ， int:1..100，4>"},
+				{Param: "target host", Type: "hostname", Placeholder: "<target host，hostname>"},
+			},
+		},
+		{
+			name:   "enum options",
+			script: "git checkout <分支,enum:main|dev | release,main>",
+			want: []model.ParamInfo{
+				{Param: "分支", Type: "enum", Default: "main", Options: []string{"main", "dev", "release"}, Placeholder: "<分支,enum:main|dev | release,main>"},
+			},
+		},
+		{
+			name:   "regex with a quantifier",
+			script: "echo <代码,regex:[a-z]{2,5}-[0-9]{3},ab-123,项目代码>",
+			want: []model.ParamInfo{
+				{Param: "代码", Type: "regex:[a-z]{2,5}-[0-9]{3}", Default: "ab-123", Description: "项目代码", Placeholder: "<代码,regex:[a-z]{2,5}-[0-9]{3},ab-123,项目代码>"},
+			},
+		},
+		{
+			name:   "repeated placeholder",
+			script: "cp <文件,file> <文件,file>.bak",
+			want: []model.ParamInfo{
+				{Param: "文件", Type: "file", Placeholder: "<文件,file>"},
+			},
+		},
+		{
+			name:   "not a placeholder",
+			script: "cat <input.txt > out.txt; echo <<EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package risk
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"wen-ai-cli/shell"
+)
+
+// Finding 脚本中命中规则的一条命令
+type Finding struct {
+	RuleID   string   // 命中的规则
+	Severity Severity // 危险等级
+	Message  string   // 提示
+	Command  string   // 命中的命令，已规整
+}
+
+// commandPrefixes 执行其他命令的前缀命令，分析时跳过，例如 sudo rm -rf / 按 rm -rf / 分析
+var commandPrefixes = map[string]bool{
+	"sudo": true, "doas": true, "env": true, "nohup": true, "time": true,
+	"command": true, "builtin": true, "exec": true, "nice": true, "xargs": true,
+}
+
+// changeDirCommands 切换工作目录的命令
+var changeDirCommands = map[string]bool{"cd": true, "pushd": true, "chdir": true}
+
+// dangerousDirPattern 匹配根目录和用户目录，切换到这些目录后 rm -rf * 等同于删除整个目录
+var dangerousDirPattern = regexp.MustCompile(`^(/|~/?|\$\{?HOME\}?/?|/home/?|/root/?)$`)
+
+// Analyze 分析脚本中的危险命令，按出现顺序返回，同一命令相同提示的规则只返回一次。
+// 前面的命令切换到根目录或用户目录时，后续命令中的 *、. 等相对路径按该目录分析，例如 cd / && rm -rf *
+func Analyze(script string) []Finding {
+	var findings []Finding
+	rules := Rules()
+	cdCommand, dir := "", ""
+	for _, command := range splitCommands(script, shell.Current().Dialect) {
+		if target, ok := changeDirTarget(command); ok {
+			cdCommand, dir = "", ""
+			if dangerousDirPattern.MatchString(target) {
+				cdCommand, dir = command, target
+			}
+			continue
+		}
+		analyzed := command
+		if dir != "" {
+			analyzed = resolveRelative(command, dir)
+			if analyzed != command {
+				command = cdCommand + " && " + command
+			}
+		}
+		// 规则以 ^ 开头时匹配管道中每一段命令的开头，例如 ls | xargs rm -rf /
+		candidates := []string{analyzed}
+		if segments := strings.Split(analyzed, " | "); len(segments) > 1 {
+			candidates = append(candidates, segments...)
+		}
+		seen := map[string]bool{}
+		for i := range rules {
+			rule := &rules[i]
+			if !slices.ContainsFunc(candidates, rule.matches) {
+				continue
+			}
+			message := rule.message()
+			if seen[message] {
+				continue
+			}
+			seen[message] = true
+			findings = append(findings, Finding{
+				RuleID:   rule.ID,
+				Severity: rule.severity,
+				Message:  message,
+				Command:  command,
+			})
+		}
+	}
+	return findings
+}
+
+// Highest 返回最高的危险等级，没有命中时返回 0
+func Highest(findings []Finding) Severity {
+	var highest Severity
+	for _, finding := range findings {
+		highest = max(highest, finding.Severity)
+	}
+	return highest
+}
+
+// changeDirTarget 获取切换工作目录命令的目标目录，没有参数时为用户目录；不是切换目录的命令时返回 false
+func changeDirTarget(command string) (string, bool) {
+	words := strings.Split(command, " ")
+	if !changeDirCommands[words[0]] || strings.Contains(command, " | ") {
+		return "", false
+	}
+	for _, word := range words[1:] {
+		// 跳过 cd -P 等选项，cd - 返回上一个目录，无法确定
+		if strings.HasPrefix(word, "-") && word != "-" {
+			continue
+		}
+		return word, true
+	}
+	return "~", true
+}
+
+// resolveRelative 将命令参数中的 .、*、./* 等相对路径替换为 dir 下的路径
+func resolveRelative(command string, dir string) string {
+	words := strings.Split(command, " ")
+	base := strings.TrimSuffix(dir, "/")
+	for i := 1; i < len(words); i++ {
+		switch words[i] {
+		case ".", "./":
+			words[i] = dir
+		case "*", "./*":
+			words[i] = base + "/*"
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitCommands 将脚本拆分为规整后的命令：按换行、;、&&、|| 和 & 拆分，管道保留在同一条命令中；
+// 去掉引号、注释和命令前缀，参数之间以一个空格分隔
+func splitCommands(script string, dialect shell.Dialect) []string {
+	escape := '\\'
+	if dialect == shell.PowerShell {
+		escape = '`'
+	}
+
+	var commands []string
+	var words []string
+	var word strings.Builder
+	inWord := false
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endCommand := func() {
+		endWord()
+		if command := normalizeCommand(words); command != "" {
+			commands = append(commands, command)
+		}
+		words = nil
+	}
+
+	runes := []rune(script)
+	quote := rune(0)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == escape && next != 0:
+				word.WriteRune(next)
+				i++
+			case r == '"':
+				quote = 0
+			default:
+				word.WriteRune(r)
+			}
+		case r == escape && next == '\n':
+			// 续行
+			i++
+		case r == escape && next != 0:
+			word.WriteRune(next)
+			inWord = true
+			i++
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '#' && !inWord:
+			// 注释到行尾
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case r == '\n' || r == ';':
+			endCommand()
+		case r == '&' && next == '&', r == '|' && next == '|':
+			endCommand()
+			i++
+		case r == '&' && next != '>' && (word.Len() == 0 || !strings.HasSuffix(word.String(), ">")):
+			// 后台运行，2>&1 和 &> 中的 & 不是命令分隔符
+			endCommand()
+		case r == '|':
+			endWord()
+			words = append(words, "|")
+		case r == ' ' || r == '\t' || r == '\r':
+			endWord()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	endCommand()
+	return commands
+}
+
+// normalizeCommand 去掉每段管道开头的变量赋值和命令前缀，命令名称只保留文件名，拼接为一行
+func normalizeCommand(words []string) string {
+	var result []string
+	start := true
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "|" {
+			result = append(result, word)
+			start = true
+			continue
+		}
+		if start {
+			name := word
+			if strings.ContainsAny(word, `/\`) && strings.Trim(word, `/\`) != "" {
+				name = filepath.Base(strings.ReplaceAll(word, `\`, "/"))
+			}
+			switch {
+			case commandPrefixes[name]:
+				// 跳过前缀命令的选项，例如 sudo -u root、nice -n 10
+				for i+1 < len(words) && strings.HasPrefix(words[i+1], "-") {
+					i++
+					if (name == "sudo" || name == "nice") && len(words[i]) == 2 && strings.ContainsAny(words[i], "ugn") {
+						i++
+					}
+				}
+				continue
+			case strings.Contains(word, "=") && !strings.HasPrefix(word, "="):
+				// 变量赋值
+				continue
+			}
+			word = name
+			start = false
+		}
+		result = append(result, word)
+	}
+	return strings.Join(result, " ")
+}
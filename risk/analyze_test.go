@@ -0,0 +1,66 @@
+package risk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tests := []struct {
+		name    string
+		script  string
+		rules   []string
+		command string
+	}{
+		{name: "rm root", script: "sudo rm -rf /", rules: []string{"rm-root"}, command: "rm -rf /"},
+		{name: "rm home with quotes", script: `rm -r -f "$HOME"`, rules: []string{"rm-root"}, command: "rm -r -f $HOME"},
+		{name: "rm in a pipe", script: "ls | xargs rm -rf /", rules: []string{"rm-root"}, command: "ls | rm -rf /"},
+		{name: "rm subdirectory", script: "rm -rf /tmp/build"},
+		{name: "no preserve root", script: "rm -r --no-preserve-root /", rules: []string{"rm-root"}, command: "rm -r --no-preserve-root /"},
+		{name: "cd root then rm star", script: "cd / && rm -rf *", rules: []string{"rm-root"}, command: "cd / && rm -rf *"},
+		{name: "cd home then rm dot", script: "cd ~\nrm -rf .", rules: []string{"rm-root"}, command: "cd ~ && rm -rf ."},
+		{name: "bare cd then rm star", script: "cd; rm -rf ./*", rules: []string{"rm-root"}, command: "cd && rm -rf ./*"},
+		{name: "cd elsewhere resets", script: "cd / && cd /tmp/build && rm -rf *"},
+		{name: "cd project then rm star", script: "cd ~/project && rm -rf *"},
+		{name: "find root delete", script: "find / -name '*.log' -delete", rules: []string{"find-delete-root"}, command: "find / -name *.log -delete"},
+		{name: "find home exec rm", script: "sudo find ~ -type f -exec rm {} +", rules: []string{"find-delete-root"}, command: "find ~ -type f -exec rm {} +"},
+		{name: "cd root then find dot", script: "cd / && find . -delete", rules: []string{"find-delete-root"}, command: "cd / && find . -delete"},
+		{name: "find subdirectory delete", script: "find /tmp/build -name '*.o' -delete"},
+		{name: "find root without delete", script: "find / -name passwd"},
+		{name: "mkfs", script: "mkfs.ext4 /dev/sdb1", rules: []string{"mkfs"}, command: "mkfs.ext4 /dev/sdb1"},
+		{name: "dd to device", script: "dd if=disk.img of=/dev/sda bs=4M", rules: []string{"dd-device"}, command: "dd if=disk.img of=/dev/sda bs=4M"},
+		{name: "redirect to device", script: "echo 1 > /dev/nvme0n1", rules: []string{"write-device"}, command: "echo 1 > /dev/nvme0n1"},
+		{name: "chmod 777 recursive", script: "chmod -R 777 ./www", rules: []string{"chmod-777"}, command: "chmod -R 777 ./www"},
+		{name: "curl pipe shell", script: "curl -fsSL https://example.com/i.sh | sudo bash", rules: []string{"curl-pipe-shell"}, command: "curl -fsSL https://example.com/i.sh | bash"},
+		{name: "shutdown", script: "sleep 60; shutdown -h now", rules: []string{"shutdown"}, command: "shutdown -h now"},
+		{name: "git force push", script: "git push -f origin main", rules: []string{"git-force"}, command: "git push -f origin main"},
+		{name: "quoted in echo", script: `echo "rm -rf /"`},
+		{name: "comment", script: "# rm -rf /\nls -la"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Analyze(tt.script)
+			var got []string
+			for _, finding := range findings {
+				got = append(got, finding.RuleID)
+				if finding.Command != tt.command {
+					t.Errorf("Analyze(%q) command = %q, want %q", tt.script, finding.Command, tt.command)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.rules) {
+				t.Errorf("Analyze(%q) rules = %v, want %v", tt.script, got, tt.rules)
+			}
+		})
+	}
+}
+
+func TestHighest(t *testing.T) {
+	findings := []Finding{{Severity: SeverityWarning}, {Severity: SeverityCritical}, {Severity: SeverityHigh}}
+	if got := Highest(findings); got != SeverityCritical {
+		t.Errorf("Highest() = %v, want %v", got, SeverityCritical)
+	}
+	if got := Highest(nil); got != 0 {
+		t.Errorf("Highest(nil) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,269 @@
+// Package risk 按规则分析待执行脚本中的危险命令，例如删除根目录、格式化磁盘、下载后直接执行的脚本等，
+// 内置规则之外可以在 ~/.wenai/risk_rules.json 中添加、覆盖或关闭规则
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/setup"
+
+	"github.com/gookit/i18n"
+)
+
+// Severity 危险等级
+type Severity int
+
+const (
+	SeverityWarning  Severity = iota + 1 // 需要留意，只展示提示
+	SeverityHigh                         // 高危，需要再次确认
+	SeverityCritical                     // 严重，需要输入确认词，关闭交互时拒绝运行
+)
+
+// severityNames 规则文件中使用的危险等级名称
+var severityNames = map[string]Severity{
+	"warning":  SeverityWarning,
+	"high":     SeverityHigh,
+	"critical": SeverityCritical,
+}
+
+// severityOff 规则文件中表示关闭同名内置规则的等级
+const severityOff = "off"
+
+// ParseSeverity 解析危险等级名称，不区分大小写，空字符串表示提醒级别
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return SeverityWarning, nil
+	}
+	severity, ok := severityNames[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown severity %q", name)
+	}
+	return severity, nil
+}
+
+// Name 返回危险等级在规则文件和命令行参数中使用的名称
+func (s Severity) Name() string {
+	for name, severity := range severityNames {
+		if severity == s {
+			return name
+		}
+	}
+	return ""
+}
+
+// String 返回危险等级的多语言名称
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return i18n.Dtr("riskCritical")
+	case SeverityHigh:
+		return i18n.Dtr("riskHigh")
+	default:
+		return i18n.Dtr("riskWarning")
+	}
+}
+
+// Rule 危险命令规则，一条命令匹配全部正则表达式时命中。
+// 正则表达式匹配的是规整后的命令：去掉引号和 sudo、env 等前缀，参数之间以一个空格分隔
+type Rule struct {
+	ID         string   `json:"id"`       // 规则标识，与内置规则相同时覆盖内置规则
+	Severity   string   `json:"severity"` // 危险等级：warning、high、critical，off 表示关闭规则，为空时沿用内置规则的等级或使用 warning
+	Patterns   []string `json:"patterns"` // 正则表达式，全部匹配时命中
+	Message    string   `json:"message"`  // 命中时的提示
+	messageKey string   // 内置规则的多语言提示
+	severity   Severity
+	disabled   bool
+	compiled   []*regexp.Regexp
+}
+
+// ruleFile 规则文件的内容
+type ruleFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// 常用的参数写法：递归参数、根目录和用户目录
+const (
+	recursiveFlag = ` (-[^ -]*[rR][^ ]*|--recursive)( |$)`
+	rootOrHome    = ` (/\*?|~/?\*?|\$\{?HOME\}?/?\*?|/home/?\*?|/root/?\*?)( |$)`
+	blockDevice   = `/dev/(sd|hd|vd|xvd|nvme|mmcblk|disk|rdisk|md|dm-|mapper/)`
+)
+
+// builtinRules 内置规则
+var builtinRules = []Rule{
+	{ID: "rm-root", severity: SeverityCritical, messageKey: "riskRmRoot",
+		Patterns: []string{`^rm( |$)`, recursiveFlag, rootOrHome}},
+	{ID: "find-delete-root", severity: SeverityCritical, messageKey: "riskFindDelete",
+		Patterns: []string{`^find` + rootOrHome, ` (-delete|-exec (sudo )?rm)( |$)`}},
+	{ID: "rm-no-preserve-root", severity: SeverityCritical, messageKey: "riskRmRoot",
+		Patterns: []string{`^rm .*--no-preserve-root`}},
+	{ID: "mkfs", severity: SeverityCritical, messageKey: "riskMkfs",
+		Patterns: []string{`^(mkfs(\.[a-z0-9]+)?|mke2fs|mkswap|wipefs)( |$)`}},
+	{ID: "dd-device", severity: SeverityCritical, messageKey: "riskDevice",
+		Patterns: []string{`^dd( |$)`, ` of=` + blockDevice}},
+	{ID: "write-device", severity: SeverityCritical, messageKey: "riskDevice",
+		Patterns: []string{`> ?` + blockDevice}},
+	{ID: "chmod-root", severity: SeverityCritical, messageKey: "riskChmodRoot",
+		Patterns: []string{`^(chmod|chown|chgrp)( |$)`, recursiveFlag, rootOrHome}},
+	{ID: "chmod-777", severity: SeverityHigh, messageKey: "riskChmod777",
+		Patterns: []string{`^chmod( |$)`, recursiveFlag, ` (0?777|a\+rwx|ugo\+rwx)( |$)`}},
+	{ID: "fork-bomb", severity: SeverityCritical, messageKey: "riskForkBomb",
+		Patterns: []string{`:\(\) ?\{ ?: ?\| ?:`}},
+	{ID: "curl-pipe-shell", severity: SeverityHigh, messageKey: "riskPipeShell",
+		Patterns: []string{`(^|\| )(curl|wget|fetch|iwr|irm|invoke-webrequest|invoke-restmethod)( |$)`,
+			`\| (sudo )?((ba|z|k|da|fi)?sh|iex|invoke-expression|python[0-9.]*)( |$)`}},
+	{ID: "shell-download", severity: SeverityHigh, messageKey: "riskPipeShell",
+		Patterns: []string{`^((ba|z|k|da)?sh|source|\.)( .*)? (<\(|\$\()(curl|wget) `}},
+	{ID: "firewall-flush", severity: SeverityHigh, messageKey: "riskFirewall",
+		Patterns: []string{`^((ip6?tables|iptables-legacy|iptables-nft)( .*)? (-F|--flush)|nft flush ruleset|ufw (disable|reset))( |$)`}},
+	{ID: "shutdown", severity: SeverityHigh, messageKey: "riskShutdown",
+		Patterns: []string{`^(shutdown|reboot|halt|poweroff|init [06]|systemctl (poweroff|reboot|halt|kexec)|stop-computer|restart-computer)( |$)`}},
+	{ID: "kill-all", severity: SeverityHigh, messageKey: "riskKillAll",
+		Patterns: []string{`^kill( -[^ ]+)* -1( |$)`}},
+	{ID: "remove-item-root", severity: SeverityCritical, messageKey: "riskRmRoot",
+		Patterns: []string{`^(remove-item|rm|del|rd|rmdir|ri)( |$)`, ` -r(ecurse)?( |$)`, ` ([a-z]:\\?|\$env:(systemdrive|userprofile)\\?|~)( |$)`}},
+	{ID: "format-volume", severity: SeverityCritical, messageKey: "riskMkfs",
+		Patterns: []string{`^(format-volume|clear-disk|format [a-z]:)( |$)`}},
+	{ID: "git-force", severity: SeverityWarning, messageKey: "riskGitForce",
+		Patterns: []string{`^git (push .*(-f|--force)|reset --hard|clean -[a-z]*f)( |$)`}},
+}
+
+var (
+	rulesOnce sync.Once
+	rules     []Rule
+)
+
+// Rules 返回生效的规则：内置规则加上规则文件中的规则，规则文件中同名规则覆盖内置规则
+func Rules() []Rule {
+	rulesOnce.Do(func() {
+		rules = loadRules()
+	})
+	return rules
+}
+
+// loadRules 编译内置规则并合并规则文件，规则文件无效时只使用内置规则
+func loadRules() []Rule {
+	merged := make([]Rule, 0, len(builtinRules))
+	for _, rule := range builtinRules {
+		rule.compiled = compilePatterns(rule.Patterns)
+		merged = append(merged, rule)
+	}
+
+	userRules, err := readRuleFile(setup.GetRiskRulesFilePath())
+	if err != nil {
+		logger.Warnf(i18n.Dtr("riskRulesInvalid"), setup.GetRiskRulesFilePath(), err)
+		return merged
+	}
+	for _, rule := range userRules {
+		index := -1
+		for i := range merged {
+			if merged[i].ID == rule.ID {
+				index = i
+				break
+			}
+		}
+		switch {
+		case rule.disabled:
+			if index >= 0 {
+				merged = append(merged[:index], merged[index+1:]...)
+			}
+		case index >= 0:
+			// 只修改等级或提示时沿用内置规则的其他设置
+			if rule.severity == 0 {
+				rule.severity = merged[index].severity
+			}
+			if len(rule.compiled) == 0 {
+				rule.compiled = merged[index].compiled
+			}
+			if rule.Message == "" {
+				rule.messageKey = merged[index].messageKey
+			}
+			merged[index] = rule
+		case len(rule.compiled) > 0:
+			if rule.severity == 0 {
+				rule.severity = SeverityWarning
+			}
+			merged = append(merged, rule)
+		}
+	}
+	return merged
+}
+
+// readRuleFile 读取并校验规则文件，文件不存在时返回空列表
+func readRuleFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file ruleFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rules[%d]: id is required", i)
+		}
+		switch name := strings.ToLower(strings.TrimSpace(rule.Severity)); name {
+		case "":
+		case severityOff:
+			rule.disabled = true
+		default:
+			severity, ok := severityNames[name]
+			if !ok {
+				return nil, fmt.Errorf("rule %s: unknown severity %q", rule.ID, rule.Severity)
+			}
+			rule.severity = severity
+		}
+		for _, pattern := range rule.Patterns {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %v", rule.ID, err)
+			}
+			rule.compiled = append(rule.compiled, compiled)
+		}
+	}
+	return file.Rules, nil
+}
+
+// compilePatterns 编译内置规则的正则表达式，内置规则不区分大小写
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled = append(compiled, regexp.MustCompile("(?i)"+pattern))
+	}
+	return compiled
+}
+
+// matches 返回命令是否匹配规则的全部正则表达式
+func (r *Rule) matches(command string) bool {
+	if len(r.compiled) == 0 {
+		return false
+	}
+	for _, pattern := range r.compiled {
+		if !pattern.MatchString(command) {
+			return false
+		}
+	}
+	return true
+}
+
+// message 返回规则命中时的提示
+func (r *Rule) message() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	if r.messageKey != "" {
+		return i18n.Dtr(r.messageKey)
+	}
+	return r.ID
+}
@@ -0,0 +1,14 @@
+package setup
+
+// allowRisk 通过 --allow-risk 参数指定关闭交互时允许运行的最高危险等级，为空时只允许提醒级别
+var allowRisk string
+
+// SetAllowRisk 设置关闭交互时允许运行的最高危险等级：warning、high 或 critical
+func SetAllowRisk(level string) {
+	allowRisk = level
+}
+
+// GetAllowRisk 获取关闭交互时允许运行的最高危险等级，未指定时返回空字符串
+func GetAllowRisk() string {
+	return allowRisk
+}
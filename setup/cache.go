@@ -0,0 +1,17 @@
+package setup
+
+// cacheDisabled 通过 --no-cache 参数关闭本次运行的回答缓存
+var cacheDisabled bool
+
+// SetCacheDisabled 设置是否关闭本次运行的回答缓存
+func SetCacheDisabled(disabled bool) {
+	cacheDisabled = disabled
+}
+
+// IsCacheEnabled 返回本次运行是否使用回答缓存，录制或回放 cassette 时不使用缓存
+func IsCacheEnabled() bool {
+	if GetRecordCassette() != "" || GetReplayCassette() != "" {
+		return false
+	}
+	return !cacheDisabled && GetConfig().Cache.IsEnabled()
+}
@@ -0,0 +1,26 @@
+package setup
+
+// 通过 --record、--replay 参数或 WENAI_RECORD、WENAI_REPLAY 环境变量指定的 cassette 文件
+var (
+	recordCassette string
+	replayCassette string
+)
+
+// SetCassette 设置本次运行录制或回放使用的 cassette 文件，同时指定时以回放为准
+func SetCassette(record string, replay string) {
+	recordCassette = record
+	replayCassette = replay
+}
+
+// GetRecordCassette 获取录制使用的 cassette 文件，未开启录制时返回空字符串
+func GetRecordCassette() string {
+	if replayCassette != "" {
+		return ""
+	}
+	return recordCassette
+}
+
+// GetReplayCassette 获取回放使用的 cassette 文件，未开启回放时返回空字符串
+func GetReplayCassette() string {
+	return replayCassette
+}
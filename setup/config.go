@@ -45,12 +45,16 @@ func InitConfig() {
 
 func createDefaultConfig(configFilePath string) error {
 	// Config结构体转换为json，写入文件
+	enabled := true
 	emptyCfg := model.Config{
-		DefaultLang: "zh-CN",
-		OpenAI: model.OpenAI{
-			APIKey:  "",
-			BaseURL: "",
-			Model:   "",
+		DefaultLang:   "zh-CN",
+		ActiveProfile: DefaultProfileName,
+		Profiles: map[string]model.Profile{
+			DefaultProfileName: {
+				APIKey:  "",
+				BaseURL: "",
+				Model:   "",
+			},
 		},
 		Logger: model.Logger{
 			Console: model.Console{
@@ -72,6 +76,29 @@ func createDefaultConfig(configFilePath string) error {
 			EnablePlatformPerception: true,
 			EnableWorkUserAndDir:     true,
 		},
+		Retry: model.RetryConfig{
+			MaxRetries:     3,
+			InitialDelayMs: 1000,
+			MaxDelayMs:     30000,
+		},
+		Usage: model.UsageConfig{
+			ShowInFooter: false,
+			Currency:     "$",
+			Prices:       map[string]model.ModelPrice{},
+		},
+		Cache: model.CacheConfig{
+			Enabled:   &enabled,
+			TTLHours:  24 * 7,
+			MaxSizeMB: 50,
+		},
+		Reasoning: model.ReasoningConfig{
+			Display: model.ReasoningCollapse,
+		},
+		ParamHistory: model.ParamHistoryConfig{
+			Enabled:      &enabled,
+			PerDirectory: false,
+			MaxValues:    10,
+		},
 	}
 	jsonData, err := json.Marshal(emptyCfg)
 	if err != nil {
@@ -87,6 +114,28 @@ func loadConfig() {
 	if err != nil {
 		panic("解析配置文件失败: " + err.Error())
 	}
+	migrateLegacyOpenAI()
+}
+
+// migrateLegacyOpenAI 将旧版的 openai 配置块迁移为 default 配置档
+func migrateLegacyOpenAI() {
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]model.Profile{}
+	}
+	if cfg.OpenAI != nil {
+		if _, ok := cfg.Profiles[DefaultProfileName]; !ok {
+			cfg.Profiles[DefaultProfileName] = model.Profile{
+				APIKey:  cfg.OpenAI.APIKey,
+				BaseURL: cfg.OpenAI.BaseURL,
+				Model:   cfg.OpenAI.Model,
+			}
+		}
+		// 迁移完成后，保存配置时不再写出旧版配置块
+		cfg.OpenAI = nil
+	}
+	if cfg.ActiveProfile == "" {
+		cfg.ActiveProfile = DefaultProfileName
+	}
 }
 
 // SaveConfig 保存配置
@@ -119,6 +168,30 @@ func GetConfigFilePath() string {
 	return filepath.Join(appDir, "conf.json")
 }
 
+// GetUsageFilePath 获取 token 用量账本文件路径
+func GetUsageFilePath() string {
+	appDir := GetAppDir()
+	return filepath.Join(appDir, "usage.jsonl")
+}
+
+// GetCacheDir 获取回答缓存目录
+func GetCacheDir() string {
+	appDir := GetAppDir()
+	return filepath.Join(appDir, "cache")
+}
+
+// GetParamHistoryFilePath 获取参数历史值文件路径
+func GetParamHistoryFilePath() string {
+	appDir := GetAppDir()
+	return filepath.Join(appDir, "params.json")
+}
+
+// GetRiskRulesFilePath 获取危险命令规则文件路径
+func GetRiskRulesFilePath() string {
+	appDir := GetAppDir()
+	return filepath.Join(appDir, "risk_rules.json")
+}
+
 // GetLogFilePath 获取日志文件路径
 func GetLogFilePath() string {
 	appDir := GetAppDir()
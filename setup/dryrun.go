@@ -0,0 +1,14 @@
+package setup
+
+// dryRun 通过 --dry-run 参数只展示将要执行的脚本，不实际执行
+var dryRun bool
+
+// SetDryRun 设置本次运行是否只展示将要执行的脚本
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// IsDryRun 返回本次运行是否只展示将要执行的脚本而不执行
+func IsDryRun() bool {
+	return dryRun
+}
@@ -0,0 +1,19 @@
+package setup
+
+import "wen-ai-cli/model"
+
+// generationOverride 通过 --temperature、--max-tokens 参数指定的本次运行生成参数
+var generationOverride model.GenerationOptions
+
+// SetGenerationOverride 设置本次运行的生成参数，已填写的字段优先于配置文件
+func SetGenerationOverride(options model.GenerationOptions) {
+	generationOverride = options
+}
+
+// GetGenerationOptions 获取子命令实际使用的生成参数，
+// 优先级从低到高依次为：配置档、按子命令的配置、命令行参数
+func GetGenerationOptions(command string, profile model.GenerationOptions) model.GenerationOptions {
+	return profile.
+		Merge(GetConfig().Generation[command]).
+		Merge(generationOverride)
+}
@@ -1,8 +1,6 @@
 package setup
 
 import (
-	"path/filepath"
-
 	"wen-ai-cli/assets"
 
 	"github.com/gookit/i18n"
@@ -12,14 +10,9 @@ import (
 func InitLang() {
 	config := GetConfig()
 	targetLangDir := GetLangDir()
-	langFiles, err := filepath.Glob(filepath.Join(targetLangDir, "*.ini"))
-	if err != nil {
-		panic("获取语言文件失败: " + err.Error())
-	}
-	if len(langFiles) == 0 {
-		if err := assets.CopyLangFiles(targetLangDir); err != nil {
-			panic("复制语言文件失败: " + err.Error())
-		}
+	// 每次启动都同步语言文件，升级后新增的翻译追加到已有文件中
+	if err := assets.SyncLangFiles(targetLangDir); err != nil {
+		panic("同步语言文件失败: " + err.Error())
 	}
 
 	defaultLang := config.DefaultLang
@@ -0,0 +1,44 @@
+package setup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gookit/i18n"
+)
+
+var (
+	// paramOverrides 通过 --param name=value 参数预先指定的参数值，按出现顺序保存
+	paramOverrides [][2]string
+	// nonInteractive 通过 --non-interactive 参数关闭所有交互提示
+	nonInteractive bool
+)
+
+// SetParamOverrides 解析 --param 参数，每一项格式为 name=value，值中可以包含 =
+func SetParamOverrides(values []string) error {
+	paramOverrides = nil
+	for _, value := range values {
+		name, paramValue, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return fmt.Errorf(i18n.Dtr("paramFlagInvalid"), value)
+		}
+		paramOverrides = append(paramOverrides, [2]string{name, paramValue})
+	}
+	return nil
+}
+
+// GetParamOverrides 获取 --param 参数预先指定的参数值，每项为名称和值，同名参数以最后一次为准
+func GetParamOverrides() [][2]string {
+	return paramOverrides
+}
+
+// SetNonInteractive 设置本次运行是否关闭所有交互提示
+func SetNonInteractive(enabled bool) {
+	nonInteractive = enabled
+}
+
+// IsNonInteractive 返回本次运行是否关闭所有交互提示
+func IsNonInteractive() bool {
+	return nonInteractive
+}
@@ -0,0 +1,68 @@
+package setup
+
+import (
+	"fmt"
+	"sort"
+
+	"wen-ai-cli/model"
+
+	"github.com/gookit/i18n"
+)
+
+// DefaultProfileName 默认配置档名称
+const DefaultProfileName = "default"
+
+// profileOverride 通过 --profile 参数指定的本次运行配置档
+var profileOverride string
+
+// SetProfileOverride 设置本次运行使用的配置档，为空时使用配置文件中的 activeProfile
+func SetProfileOverride(name string) {
+	profileOverride = name
+}
+
+// GetActiveProfileName 获取本次运行使用的配置档名称
+func GetActiveProfileName() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	if name := GetConfig().ActiveProfile; name != "" {
+		return name
+	}
+	return DefaultProfileName
+}
+
+// GetProfile 获取指定名称的配置档
+func GetProfile(name string) (*model.Profile, error) {
+	profile, ok := GetConfig().Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf(i18n.Dtr("profileNotFound"), name)
+	}
+	return &profile, nil
+}
+
+// GetActiveProfile 获取本次运行使用的配置档
+func GetActiveProfile() (*model.Profile, error) {
+	return GetProfile(GetActiveProfileName())
+}
+
+// ValidateActiveProfile 校验本次运行使用的配置档是否存在且信息完整
+func ValidateActiveProfile() error {
+	profile, err := GetActiveProfile()
+	if err != nil {
+		return err
+	}
+	if !profile.IsComplete() {
+		return fmt.Errorf(i18n.Dtr("profileIncomplete"), GetActiveProfileName())
+	}
+	return nil
+}
+
+// GetProfileNames 获取按名称排序的配置档列表
+func GetProfileNames() []string {
+	names := make([]string, 0, len(GetConfig().Profiles))
+	for name := range GetConfig().Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
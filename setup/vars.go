@@ -7,4 +7,9 @@ const (
 	ConfigCmdAlias = "c"
 	ChatCmd        = "chat"
 	ManualCmd      = "man"
+	ProfileCmd     = "profile"
+	UsageCmd       = "usage"
+	CacheCmd       = "cache"
+	ModelsCmd      = "models"
+	OnceCmd        = "once" // 单轮提问没有子命令，统计用量时使用该名称
 )
@@ -0,0 +1,346 @@
+package shell
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/gookit/i18n"
+)
+
+// Context 脚本中某个位置所处的引号环境
+type Context int
+
+const (
+	Unquoted      Context = iota // 不在引号内
+	SingleQuoted                 // 在单引号内
+	DoubleQuoted                 // 在双引号内
+	Comment                      // 在注释内
+	Heredoc                      // 在分隔符未加引号的 heredoc 或 PowerShell @" "@ 中，会展开变量
+	QuotedHeredoc                // 在分隔符加了引号的 heredoc 或 PowerShell @' '@ 中，内容原样使用
+)
+
+var (
+	// posixSafe 不需要加引号的 POSIX 和 fish 参数值
+	posixSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+	// fishSafe fish 中 % 开头可能被展开，不视为安全字符
+	fishSafe = regexp.MustCompile(`^[A-Za-z0-9_@+=:,./-]+$`)
+	// powerShellSafe PowerShell 中 - 开头会被解析为参数名，逗号会组成数组
+	powerShellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:\\][A-Za-z0-9_./:\\-]*$`)
+)
+
+// heredoc 等待开始或正在读取的 heredoc
+type heredoc struct {
+	delimiter string // 结束行
+	quoted    bool   // 分隔符加了引号，内容不展开变量
+	stripTabs bool   // <<- 形式，结束行前可以有制表符
+}
+
+// ContextAt 获取 script 末尾所处的引号环境，用于判断紧接着的占位是否已在引号内。
+// 词首的 # 开始注释，注释中的引号不影响判断；POSIX 的 << heredoc 和 PowerShell 的 here-string 从下一行开始，到结束行为止
+func (d Dialect) ContextAt(script string) Context {
+	ctx := Unquoted
+	wordStart := true
+	blockComment := false // PowerShell <# #> 注释
+	arithmetic := 0       // (( )) 算术表达式中的 << 是位移运算
+	var pending []heredoc // 当前行中等待开始的 heredoc
+	lineStart := 0
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		next := byte(0)
+		if i+1 < len(script) {
+			next = script[i+1]
+		}
+		switch ctx {
+		case Unquoted:
+			switch {
+			case c == d.escapeChar():
+				i++
+				wordStart = false
+			case c == '\'' && d == PowerShell && i > 0 && script[i-1] == '@' && (next == '\n' || next == '\r'):
+				ctx = QuotedHeredoc
+				pending = []heredoc{{delimiter: "'@"}}
+				i = skipLine(script, i)
+				lineStart = i + 1
+			case c == '"' && d == PowerShell && i > 0 && script[i-1] == '@' && (next == '\n' || next == '\r'):
+				ctx = Heredoc
+				pending = []heredoc{{delimiter: `"@`}}
+				i = skipLine(script, i)
+				lineStart = i + 1
+			case c == '\'':
+				ctx = SingleQuoted
+				wordStart = false
+			case c == '"':
+				ctx = DoubleQuoted
+				wordStart = false
+			case c == '<' && next == '#' && d == PowerShell:
+				ctx, blockComment = Comment, true
+				i++
+			case c == '#' && wordStart:
+				ctx = Comment
+			case c == '(' && next == '(' && d == POSIX:
+				arithmetic++
+				i++
+				wordStart = true
+			case c == ')' && next == ')' && arithmetic > 0:
+				arithmetic--
+				i++
+				wordStart = false
+			case c == '<' && next == '<' && d == POSIX && arithmetic == 0:
+				if i+2 < len(script) && script[i+2] == '<' {
+					// <<< here-string 不是 heredoc
+					i += 2
+					wordStart = true
+					continue
+				}
+				doc, end := parseHeredoc(script, i+2)
+				if doc.delimiter != "" {
+					pending = append(pending, doc)
+				}
+				i = end - 1
+				wordStart = false
+			case c == '\n':
+				wordStart = true
+				if len(pending) > 0 {
+					ctx = heredocContext(pending[0])
+					lineStart = i + 1
+				}
+			default:
+				wordStart = strings.IndexByte(" \t\r;&|()", c) >= 0
+			}
+		case SingleQuoted:
+			switch {
+			case d == Fish && c == '\\':
+				// fish 单引号内可以使用 \' 和 \\
+				i++
+			case c == '\'' && d == PowerShell && next == '\'':
+				// PowerShell 单引号内使用 '' 表示单引号
+				i++
+			case c == '\'':
+				ctx = Unquoted
+			}
+		case DoubleQuoted:
+			switch {
+			case c == d.escapeChar():
+				i++
+			case c == '"' && d == PowerShell && next == '"':
+				i++
+			case c == '"':
+				ctx = Unquoted
+			}
+		case Comment:
+			switch {
+			case blockComment && c == '#' && next == '>':
+				ctx, blockComment = Unquoted, false
+				i++
+			case !blockComment && c == '\n':
+				// 注释结束的换行仍然开始等待中的 heredoc
+				ctx = Unquoted
+				i--
+			}
+		case Heredoc, QuotedHeredoc:
+			if c != '\n' {
+				continue
+			}
+			if isHeredocEnd(script[lineStart:i], pending[0]) {
+				pending = pending[1:]
+				ctx = Unquoted
+				if len(pending) > 0 {
+					ctx = heredocContext(pending[0])
+				}
+			}
+			lineStart = i + 1
+		}
+	}
+	return ctx
+}
+
+// parseHeredoc 解析 << 之后的 heredoc 分隔符，返回 heredoc 和分隔符之后的位置，没有分隔符时 delimiter 为空
+func parseHeredoc(script string, i int) (heredoc, int) {
+	var doc heredoc
+	if i < len(script) && script[i] == '-' {
+		doc.stripTabs = true
+		i++
+	}
+	for i < len(script) && (script[i] == ' ' || script[i] == '\t') {
+		i++
+	}
+	var delimiter strings.Builder
+	for i < len(script) && strings.IndexByte(" \t\r\n;&|()<>", script[i]) < 0 {
+		switch c := script[i]; c {
+		case '\'', '"':
+			doc.quoted = true
+			end := strings.IndexByte(script[i+1:], c)
+			if end < 0 {
+				end = len(script) - i - 1
+			}
+			delimiter.WriteString(script[i+1 : i+1+end])
+			i += end + 2
+			continue
+		case '\\':
+			doc.quoted = true
+			i++
+			if i < len(script) {
+				delimiter.WriteByte(script[i])
+			}
+		default:
+			delimiter.WriteByte(c)
+		}
+		i++
+	}
+	doc.delimiter = delimiter.String()
+	return doc, min(i, len(script))
+}
+
+// isHeredocEnd 返回一行内容是否为 heredoc 的结束行，PowerShell here-string 的结束行允许前面有空白
+func isHeredocEnd(line string, doc heredoc) bool {
+	line = strings.TrimSuffix(line, "\r")
+	switch {
+	case doc.delimiter == "'@" || doc.delimiter == `"@`:
+		line = strings.TrimLeft(line, " \t")
+	case doc.stripTabs:
+		line = strings.TrimLeft(line, "\t")
+	}
+	return line == doc.delimiter
+}
+
+// heredocContext heredoc 内容所处的引号环境
+func heredocContext(doc heredoc) Context {
+	if doc.quoted || doc.delimiter == "'@" {
+		return QuotedHeredoc
+	}
+	return Heredoc
+}
+
+// skipLine 返回 i 所在行的换行符位置，没有换行符时返回最后一个字符的位置
+func skipLine(script string, i int) int {
+	if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(script) - 1
+}
+
+// escapeChar 引号外和双引号内的转义字符
+func (d Dialect) escapeChar() byte {
+	if d == PowerShell {
+		return '`'
+	}
+	return '\\'
+}
+
+// Quote 按引号环境转义参数值，使其在脚本中作为一个完整的参数，不会被拆分或执行。
+// heredoc 中的换行可能提前结束 heredoc，参数值包含换行时返回错误
+func (d Dialect) Quote(value string, ctx Context) (string, error) {
+	switch ctx {
+	case SingleQuoted:
+		return d.escapeSingle(value), nil
+	case DoubleQuoted:
+		return d.escapeDouble(value), nil
+	case Comment:
+		// 换行会结束注释，之后的内容会被执行
+		return d.escapeComment(value), nil
+	case Heredoc, QuotedHeredoc:
+		if strings.ContainsAny(value, "\r\n") {
+			return "", errors.New(i18n.Dtr("paramHeredocNewline"))
+		}
+		if ctx == Heredoc {
+			return d.escapeHeredoc(value), nil
+		}
+		return value, nil
+	}
+	if d != PowerShell && strings.HasPrefix(value, "~/") {
+		// 保留 ~ 在引号外，使其仍能展开为用户目录
+		if rest := value[2:]; rest != "" {
+			quoted, err := d.Quote(rest, Unquoted)
+			return "~/" + quoted, err
+		}
+		return value, nil
+	}
+	if d.isSafe(value) {
+		return value, nil
+	}
+	return "'" + d.escapeSingle(value) + "'", nil
+}
+
+// isSafe 返回参数值是否可以不加引号直接使用
+func (d Dialect) isSafe(value string) bool {
+	switch d {
+	case Fish:
+		return fishSafe.MatchString(value)
+	case PowerShell:
+		return powerShellSafe.MatchString(value)
+	default:
+		return posixSafe.MatchString(value)
+	}
+}
+
+// escapeSingle 转义单引号内的内容
+func (d Dialect) escapeSingle(value string) string {
+	switch d {
+	case Fish:
+		return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	case PowerShell:
+		return strings.ReplaceAll(value, "'", "''")
+	default:
+		// POSIX 单引号内无法转义，先结束单引号，加入转义的单引号后再重新开始
+		return strings.ReplaceAll(value, "'", `'\''`)
+	}
+}
+
+// escapeDouble 转义双引号内的内容，避免变量展开和命令替换
+func (d Dialect) escapeDouble(value string) string {
+	switch d {
+	case Fish:
+		return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(value)
+	case PowerShell:
+		return strings.NewReplacer("`", "``", `"`, "`\"", `$`, "`$").Replace(value)
+	default:
+		return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`").Replace(value)
+	}
+}
+
+// escapeComment 替换注释内容中会结束注释的换行和 PowerShell 的 #>
+func (d Dialect) escapeComment(value string) string {
+	replacer := strings.NewReplacer("\r", " ", "\n", " ")
+	if d == PowerShell {
+		replacer = strings.NewReplacer("\r", " ", "\n", " ", "#>", "# >")
+	}
+	return replacer.Replace(value)
+}
+
+// escapeHeredoc 转义会展开的 heredoc 内容，引号在 heredoc 中没有特殊含义
+func (d Dialect) escapeHeredoc(value string) string {
+	if d == PowerShell {
+		return strings.NewReplacer("`", "``", `$`, "`$").Replace(value)
+	}
+	return strings.NewReplacer(`\`, `\\`, `$`, `\$`, "`", "\\`").Replace(value)
+}
+
+// EnvRef 获取在引号环境中引用环境变量的写法，变量值作为一个完整的参数。
+// 分隔符加了引号的 heredoc 不展开变量，无法引用时返回错误
+func (d Dialect) EnvRef(name string, ctx Context) (string, error) {
+	var quoted, inDouble string
+	switch d {
+	case Fish:
+		// fish 双引号内没有分隔变量名的写法，先结束双引号再拼接
+		quoted, inDouble = `"$`+name+`"`, `""$`+name+`""`
+	case PowerShell:
+		quoted, inDouble = "$env:"+name, "${env:"+name+"}"
+	default:
+		quoted, inDouble = `"${`+name+`}"`, "${"+name+"}"
+	}
+	switch ctx {
+	case DoubleQuoted, Heredoc:
+		return inDouble, nil
+	case SingleQuoted:
+		// 单引号内不会展开变量，先结束单引号再拼接
+		if d == PowerShell {
+			return `'"` + inDouble + `"'`, nil
+		}
+		return "'" + quoted + "'", nil
+	case QuotedHeredoc:
+		return "", errors.New(i18n.Dtr("paramQuotedHeredocSecret"))
+	default:
+		return quoted, nil
+	}
+}
@@ -0,0 +1,128 @@
+package shell
+
+import "testing"
+
+func TestContextAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		script  string
+		want    Context
+	}{
+		{name: "posix empty", dialect: POSIX, script: "", want: Unquoted},
+		{name: "posix single", dialect: POSIX, script: "echo '", want: SingleQuoted},
+		{name: "posix double", dialect: POSIX, script: `echo "a `, want: DoubleQuoted},
+		{name: "posix closed", dialect: POSIX, script: `echo 'a' "b" `, want: Unquoted},
+		{name: "posix escaped quote", dialect: POSIX, script: `echo \' `, want: Unquoted},
+		{name: "posix escaped double in double", dialect: POSIX, script: `echo "a\" `, want: DoubleQuoted},
+		{name: "posix backslash in single", dialect: POSIX, script: `echo 'a\`, want: SingleQuoted},
+		{name: "posix comment with apostrophe", dialect: POSIX, script: "# Let's list files\nls ", want: Unquoted},
+		{name: "posix trailing comment with quote", dialect: POSIX, script: "ls # don't\necho \"", want: DoubleQuoted},
+		{name: "posix inside comment", dialect: POSIX, script: "ls # list ", want: Comment},
+		{name: "posix hash inside word", dialect: POSIX, script: "echo a#'", want: SingleQuoted},
+		{name: "posix hash after variable", dialect: POSIX, script: "echo $# '", want: SingleQuoted},
+		{name: "posix heredoc body", dialect: POSIX, script: "cat <<EOF\nname: ", want: Heredoc},
+		{name: "posix heredoc apostrophe", dialect: POSIX, script: "cat <<EOF\nit's ", want: Heredoc},
+		{name: "posix quoted heredoc", dialect: POSIX, script: "cat <<'EOF'\n$HOME ", want: QuotedHeredoc},
+		{name: "posix escaped heredoc", dialect: POSIX, script: "cat <<\\EOF\n", want: QuotedHeredoc},
+		{name: "posix heredoc ended", dialect: POSIX, script: "cat <<EOF\nit's\nEOF\nls ", want: Unquoted},
+		{name: "posix heredoc strip tabs", dialect: POSIX, script: "cat <<-END\n\tx\n\tEND\necho '", want: SingleQuoted},
+		{name: "posix heredoc after command", dialect: POSIX, script: "cat <<EOF > out.txt\n", want: Heredoc},
+		{name: "posix heredoc line not started", dialect: POSIX, script: "cat <<EOF | grep '", want: SingleQuoted},
+		{name: "posix two heredocs", dialect: POSIX, script: "cmd <<A <<'B'\na\nA\n", want: QuotedHeredoc},
+		{name: "posix here-string", dialect: POSIX, script: "cat <<< '", want: SingleQuoted},
+		{name: "posix arithmetic shift", dialect: POSIX, script: "echo $((1<<2))\nls ", want: Unquoted},
+		{name: "fish escaped quote in single", dialect: Fish, script: `echo 'a\' `, want: SingleQuoted},
+		{name: "fish comment with apostrophe", dialect: Fish, script: "# it's\nls ", want: Unquoted},
+		{name: "fish no heredoc", dialect: Fish, script: "cat <<EOF\n", want: Unquoted},
+		{name: "powershell doubled quote", dialect: PowerShell, script: "echo 'it''s ", want: SingleQuoted},
+		{name: "powershell backtick", dialect: PowerShell, script: "echo `\" ", want: Unquoted},
+		{name: "powershell comment with apostrophe", dialect: PowerShell, script: "# Let's list files\nGet-ChildItem ", want: Unquoted},
+		{name: "powershell block comment", dialect: PowerShell, script: "<# it's\n#> Get-ChildItem '", want: SingleQuoted},
+		{name: "powershell inside block comment", dialect: PowerShell, script: "<# note ", want: Comment},
+		{name: "powershell here-string", dialect: PowerShell, script: "@'\nit's ", want: QuotedHeredoc},
+		{name: "powershell expandable here-string", dialect: PowerShell, script: "$x = @\"\n", want: Heredoc},
+		{name: "powershell here-string ended", dialect: PowerShell, script: "@\"\nx\n\"@\necho '", want: SingleQuoted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.ContextAt(tt.script); got != tt.want {
+				t.Errorf("ContextAt(%q) = %v, want %v", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		value   string
+		ctx     Context
+		want    string
+		wantErr bool
+	}{
+		{name: "posix safe", dialect: POSIX, value: "a.txt", ctx: Unquoted, want: "a.txt"},
+		{name: "posix unsafe", dialect: POSIX, value: "a b; rm -rf ~", ctx: Unquoted, want: "'a b; rm -rf ~'"},
+		{name: "posix apostrophe", dialect: POSIX, value: "it's", ctx: Unquoted, want: `'it'\''s'`},
+		{name: "posix home", dialect: POSIX, value: "~/my dir", ctx: Unquoted, want: "~/'my dir'"},
+		{name: "posix single", dialect: POSIX, value: "it's", ctx: SingleQuoted, want: `it'\''s`},
+		{name: "posix double", dialect: POSIX, value: "$(id) \"`", ctx: DoubleQuoted, want: "\\$(id) \\\"\\`"},
+		{name: "posix comment", dialect: POSIX, value: "a\nrm -rf ~", ctx: Comment, want: "a rm -rf ~"},
+		{name: "posix heredoc", dialect: POSIX, value: `$(id) \ "x"`, ctx: Heredoc, want: `\$(id) \\ "x"`},
+		{name: "posix heredoc newline", dialect: POSIX, value: "a\nEOF\nrm -rf ~", ctx: Heredoc, wantErr: true},
+		{name: "posix quoted heredoc", dialect: POSIX, value: "$HOME 'x'", ctx: QuotedHeredoc, want: "$HOME 'x'"},
+		{name: "posix quoted heredoc newline", dialect: POSIX, value: "a\nb", ctx: QuotedHeredoc, wantErr: true},
+		{name: "fish percent", dialect: Fish, value: "%self", ctx: Unquoted, want: "'%self'"},
+		{name: "fish single", dialect: Fish, value: `it's \`, ctx: SingleQuoted, want: `it\'s \\`},
+		{name: "powershell dash", dialect: PowerShell, value: "-Force", ctx: Unquoted, want: "'-Force'"},
+		{name: "powershell single", dialect: PowerShell, value: "it's", ctx: SingleQuoted, want: "it''s"},
+		{name: "powershell double", dialect: PowerShell, value: "$x \"", ctx: DoubleQuoted, want: "`$x `\""},
+		{name: "powershell block comment", dialect: PowerShell, value: "#> Remove-Item", ctx: Comment, want: "# > Remove-Item"},
+		{name: "powershell here-string", dialect: PowerShell, value: "$x `", ctx: Heredoc, want: "`$x ``"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dialect.Quote(tt.value, tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Quote(%q, %v) error = %v, wantErr %v", tt.value, tt.ctx, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Quote(%q, %v) = %q, want %q", tt.value, tt.ctx, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		ctx     Context
+		want    string
+		wantErr bool
+	}{
+		{name: "posix unquoted", dialect: POSIX, ctx: Unquoted, want: `"${S}"`},
+		{name: "posix single", dialect: POSIX, ctx: SingleQuoted, want: `'"${S}"'`},
+		{name: "posix double", dialect: POSIX, ctx: DoubleQuoted, want: "${S}"},
+		{name: "posix comment", dialect: POSIX, ctx: Comment, want: `"${S}"`},
+		{name: "posix heredoc", dialect: POSIX, ctx: Heredoc, want: "${S}"},
+		{name: "posix quoted heredoc", dialect: POSIX, ctx: QuotedHeredoc, wantErr: true},
+		{name: "fish double", dialect: Fish, ctx: DoubleQuoted, want: `""$S""`},
+		{name: "powershell unquoted", dialect: PowerShell, ctx: Unquoted, want: "$env:S"},
+		{name: "powershell single", dialect: PowerShell, ctx: SingleQuoted, want: `'"${env:S}"'`},
+		{name: "powershell here-string", dialect: PowerShell, ctx: Heredoc, want: "${env:S}"},
+		{name: "powershell literal here-string", dialect: PowerShell, ctx: QuotedHeredoc, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dialect.EnvRef("S", tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EnvRef(%v) error = %v, wantErr %v", tt.ctx, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("EnvRef(%v) = %q, want %q", tt.ctx, got, tt.want)
+			}
+		})
+	}
+}
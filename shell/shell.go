@@ -0,0 +1,55 @@
+// Package shell 描述执行脚本使用的 shell，并按其引号规则转义替换到脚本中的参数值
+package shell
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"wen-ai-cli/setup"
+)
+
+// Dialect shell 的引号和转义规则
+type Dialect int
+
+const (
+	POSIX      Dialect = iota // bash、sh、zsh 等 POSIX 兼容 shell
+	Fish                      // fish
+	PowerShell                // Windows PowerShell 和 pwsh
+)
+
+// Target 执行脚本使用的 shell
+type Target struct {
+	Name    string  // shell 可执行文件名称或路径
+	Arg     string  // 传入脚本内容的参数，例如 -c、-Command
+	Dialect Dialect // 引号和转义规则
+}
+
+// Current 获取执行脚本使用的 shell，配置文件未指定时 Windows 使用 powershell，其他系统使用 bash
+func Current() Target {
+	name := setup.GetConfig().Shell
+	if name == "" {
+		name = "bash"
+		if runtime.GOOS == "windows" {
+			name = "powershell"
+		}
+	}
+	dialect := dialectOf(name)
+	arg := "-c"
+	if dialect == PowerShell {
+		arg = "-Command"
+	}
+	return Target{Name: name, Arg: arg, Dialect: dialect}
+}
+
+// dialectOf 根据 shell 名称判断引号规则，未知的 shell 按 POSIX 处理
+func dialectOf(name string) Dialect {
+	base := strings.ToLower(strings.TrimSuffix(filepath.Base(name), ".exe"))
+	switch base {
+	case "fish":
+		return Fish
+	case "powershell", "pwsh":
+		return PowerShell
+	default:
+		return POSIX
+	}
+}
@@ -0,0 +1,72 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+	"wen-ai-cli/setup"
+)
+
+// Record 单次大模型调用的 token 用量
+type Record struct {
+	Time             time.Time `json:"time"`
+	Command          string    `json:"command"` // 发起调用的子命令：once、chat、man
+	Profile          string    `json:"profile"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"promptTokens"`
+	CompletionTokens int       `json:"completionTokens"`
+}
+
+// TotalTokens 返回输入和输出 token 总数
+func (r *Record) TotalTokens() int {
+	return r.PromptTokens + r.CompletionTokens
+}
+
+// Append 将用量记录追加到账本文件，每行一条 JSON
+func Append(record Record) error {
+	path := setup.GetUsageFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load 读取 since 之后的用量记录，账本不存在时返回空列表
+func Load(since time.Time) ([]Record, error) {
+	f, err := os.Open(setup.GetUsageFilePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record Record
+		// 跳过损坏的行，避免一条坏数据导致整个报表不可用
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if record.Time.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,87 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"wen-ai-cli/setup"
+)
+
+// 汇总维度
+const (
+	GroupByDay     = "day"
+	GroupByProfile = "profile"
+	GroupByModel   = "model"
+	GroupByCommand = "command"
+)
+
+// Summary 按某一维度汇总的用量
+type Summary struct {
+	Key              string
+	Calls            int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+	Priced           bool // 是否至少有一条记录配置了单价
+}
+
+// Cost 按配置的模型单价计算费用，未配置单价时返回 false
+func Cost(modelName string, promptTokens int, completionTokens int) (float64, bool) {
+	price, ok := setup.GetConfig().Usage.Prices[modelName]
+	if !ok {
+		return 0, false
+	}
+	cost := (float64(promptTokens)*price.Prompt + float64(completionTokens)*price.Completion) / 1e6
+	return cost, true
+}
+
+// FormatCost 使用配置的货币符号格式化费用
+func FormatCost(cost float64) string {
+	currency := setup.GetConfig().Usage.Currency
+	if currency == "" {
+		currency = "$"
+	}
+	return fmt.Sprintf("%s%.4f", currency, cost)
+}
+
+// Summarize 按指定维度汇总用量记录，结果按维度值排序
+func Summarize(records []Record, groupBy string) []Summary {
+	summaries := map[string]*Summary{}
+	for _, record := range records {
+		key := groupKey(&record, groupBy)
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &Summary{Key: key}
+			summaries[key] = summary
+		}
+		summary.Calls++
+		summary.PromptTokens += record.PromptTokens
+		summary.CompletionTokens += record.CompletionTokens
+		if cost, ok := Cost(record.Model, record.PromptTokens, record.CompletionTokens); ok {
+			summary.Cost += cost
+			summary.Priced = true
+		}
+	}
+
+	result := make([]Summary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// groupKey 获取记录在指定维度上的值
+func groupKey(record *Record, groupBy string) string {
+	switch groupBy {
+	case GroupByProfile:
+		return record.Profile
+	case GroupByModel:
+		return record.Model
+	case GroupByCommand:
+		return record.Command
+	default:
+		return record.Time.Local().Format("2006-01-02")
+	}
+}
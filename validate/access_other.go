@@ -0,0 +1,14 @@
+//go:build !unix
+
+package validate
+
+import "os"
+
+// canWrite 返回路径是否可写：文件没有只读属性，目录只要存在即可在其中创建文件
+func canWrite(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.IsDir() || info.Mode().Perm()&0200 != 0
+}
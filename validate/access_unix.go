@@ -0,0 +1,10 @@
+//go:build unix
+
+package validate
+
+import "golang.org/x/sys/unix"
+
+// canWrite 返回当前用户是否有路径的写权限，按实际用户和组判断
+func canWrite(path string) bool {
+	return unix.Access(path, unix.W_OK) == nil
+}
@@ -0,0 +1,349 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"wen-ai-cli/model"
+	"wen-ai-cli/setup"
+
+	"github.com/gookit/i18n"
+)
+
+// Validator 校验参数输入值，arg 为类型名称冒号之后的部分，例如 int:1..100 中的 1..100，没有时为空
+type Validator func(input, arg string) error
+
+// validators 已注册的内置参数类型，键为小写的类型名称
+var validators = map[string]Validator{}
+
+// Register 注册参数类型，类型名称不区分大小写，同名类型会被覆盖
+func Register(name string, validator Validator) {
+	validators[strings.ToLower(name)] = validator
+}
+
+// Types 返回全部参数类型名称，包括配置文件中的自定义类型，按字母排序
+func Types() []string {
+	names := make([]string, 0, len(validators))
+	for name := range validators {
+		names = append(names, name)
+	}
+	for name := range setup.GetConfig().ParamTypes {
+		if _, ok := validators[strings.ToLower(name)]; !ok {
+			names = append(names, strings.ToLower(name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup 查找参数类型的校验器，内置类型优先于配置文件中的自定义类型
+func lookup(paramType string) (Validator, string, bool) {
+	name, arg, _ := strings.Cut(paramType, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if validator, ok := validators[name]; ok {
+		return validator, arg, true
+	}
+	for typeName, custom := range setup.GetConfig().ParamTypes {
+		if strings.EqualFold(typeName, name) {
+			return customValidator(typeName, custom), arg, true
+		}
+	}
+	return nil, arg, false
+}
+
+func init() {
+	Register("string", validateString)
+	Register("secret", validateString)
+	Register("url", validateURL)
+	Register("number", validateNumber)
+	Register("int", validateInt)
+	Register("integer", validateInt)
+	Register("port", validatePort)
+	Register("ip", validateIP)
+	Register("ipv4", validateIPv4)
+	Register("ipv6", validateIPv6)
+	Register("cidr", validateCIDR)
+	Register("hostname", validateHostname)
+	Register("file", validateFile)
+	Register("dir", validateDir)
+	Register("writable", validateWritable)
+	Register("duration", validateDuration)
+	Register("email", validateEmail)
+	Register("regex", validateRegex)
+	Register("semver", validateSemver)
+	Register("user", validateUser)
+	Register("group", validateGroup)
+}
+
+// localizedError 使用国际化文本创建错误
+func localizedError(key string, args ...any) error {
+	if len(args) == 0 {
+		return errors.New(i18n.Dtr(key))
+	}
+	return fmt.Errorf(i18n.Dtr(key), args...)
+}
+
+func validateString(input, _ string) error {
+	// 字符串类型验证 - 基本检查
+	if strings.TrimSpace(input) == "" {
+		return errors.New(setup.GetI18n().ParamEmptyError)
+	}
+	return nil
+}
+
+func validateURL(input, _ string) error {
+	// URL类型验证
+	if _, err := url.Parse(input); err != nil {
+		return errors.New(setup.GetI18n().UrlInvalidError)
+	}
+	// 检查URL是否包含协议
+	if !strings.HasPrefix(input, "http://") && !strings.HasPrefix(input, "https://") {
+		return errors.New(setup.GetI18n().UrlInvalidError)
+	}
+	return nil
+}
+
+func validateNumber(input, _ string) error {
+	if _, err := strconv.ParseFloat(strings.TrimSpace(input), 64); err != nil {
+		return localizedError("paramNumberInvalid")
+	}
+	return nil
+}
+
+// validateInt 校验整数，arg 为可选的闭区间，例如 1..100、1..、..100，区间格式错误时忽略
+func validateInt(input, arg string) error {
+	value, err := strconv.ParseInt(strings.TrimSpace(input), 10, 64)
+	if err != nil {
+		return localizedError("paramIntegerInvalid")
+	}
+	if arg == "" {
+		return nil
+	}
+	low, high, ok := parseRange(arg)
+	if !ok {
+		return nil
+	}
+	if value < low || value > high {
+		return localizedError("paramIntegerRange", arg)
+	}
+	return nil
+}
+
+// parseRange 解析 最小值..最大值 格式的区间，省略的一端不限制
+func parseRange(arg string) (int64, int64, bool) {
+	lowText, highText, ok := strings.Cut(strings.TrimSpace(arg), "..")
+	if !ok {
+		return 0, 0, false
+	}
+	low, high := int64(math.MinInt64), int64(math.MaxInt64)
+	var err error
+	if lowText = strings.TrimSpace(lowText); lowText != "" {
+		if low, err = strconv.ParseInt(lowText, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	if highText = strings.TrimSpace(highText); highText != "" {
+		if high, err = strconv.ParseInt(highText, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	return low, high, true
+}
+
+func validatePort(input, _ string) error {
+	port, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || port < 1 || port > 65535 {
+		return localizedError("paramPortInvalid")
+	}
+	return nil
+}
+
+func validateIP(input, _ string) error {
+	if net.ParseIP(strings.TrimSpace(input)) == nil {
+		return localizedError("paramIPInvalid")
+	}
+	return nil
+}
+
+func validateIPv4(input, _ string) error {
+	ip := net.ParseIP(strings.TrimSpace(input))
+	if ip == nil || ip.To4() == nil {
+		return localizedError("paramIPv4Invalid")
+	}
+	return nil
+}
+
+func validateIPv6(input, _ string) error {
+	input = strings.TrimSpace(input)
+	ip := net.ParseIP(input)
+	if ip == nil || !strings.Contains(input, ":") {
+		return localizedError("paramIPv6Invalid")
+	}
+	return nil
+}
+
+func validateCIDR(input, _ string) error {
+	if _, _, err := net.ParseCIDR(strings.TrimSpace(input)); err != nil {
+		return localizedError("paramCIDRInvalid")
+	}
+	return nil
+}
+
+// hostnamePattern RFC 1123 主机名的单个标签
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+func validateHostname(input, _ string) error {
+	host := strings.TrimSuffix(strings.TrimSpace(input), ".")
+	if host == "" || len(host) > 253 {
+		return localizedError("paramHostnameInvalid")
+	}
+	for _, label := range strings.Split(host, ".") {
+		if !hostnamePattern.MatchString(label) {
+			return localizedError("paramHostnameInvalid")
+		}
+	}
+	return nil
+}
+
+// expandHome 展开路径开头的 ~，与 shell 执行脚本时的行为保持一致
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+func validateFile(input, _ string) error {
+	info, err := os.Stat(expandHome(strings.TrimSpace(input)))
+	if err != nil {
+		return localizedError("paramFileNotFound", input)
+	}
+	if info.IsDir() {
+		return localizedError("paramNotFile", input)
+	}
+	return nil
+}
+
+func validateDir(input, _ string) error {
+	info, err := os.Stat(expandHome(strings.TrimSpace(input)))
+	if err != nil {
+		return localizedError("paramDirNotFound", input)
+	}
+	if !info.IsDir() {
+		return localizedError("paramNotDir", input)
+	}
+	return nil
+}
+
+// validateWritable 校验路径可写：已存在的文件或目录需要有写权限，不存在的路径需要上级目录存在且有写权限。
+// 输入过程中会反复校验，因此只检查权限，不在磁盘上创建文件
+func validateWritable(input, _ string) error {
+	path := expandHome(strings.TrimSpace(input))
+	if _, err := os.Stat(path); err != nil {
+		path = filepath.Dir(path)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return localizedError("paramPathNotWritable", input)
+		}
+	}
+	if !canWrite(path) {
+		return localizedError("paramPathNotWritable", input)
+	}
+	return nil
+}
+
+func validateDuration(input, _ string) error {
+	if _, err := time.ParseDuration(strings.TrimSpace(input)); err != nil {
+		return localizedError("paramDurationInvalid")
+	}
+	return nil
+}
+
+func validateEmail(input, _ string) error {
+	input = strings.TrimSpace(input)
+	address, err := mail.ParseAddress(input)
+	if err != nil || address.Address != input {
+		return localizedError("paramEmailInvalid")
+	}
+	return nil
+}
+
+// validateRegex 带有 arg 时要求输入完整匹配 arg 表达式，否则要求输入本身是合法的正则表达式
+func validateRegex(input, arg string) error {
+	if arg == "" {
+		if _, err := regexp.Compile(input); err != nil {
+			return localizedError("paramRegexInvalid", err)
+		}
+		return nil
+	}
+	re, err := regexp.Compile("^(?:" + arg + ")$")
+	if err != nil {
+		// 表达式由模型给出，无法编译时不阻塞用户输入
+		return nil
+	}
+	if !re.MatchString(input) {
+		return localizedError("paramPatternMismatch", arg)
+	}
+	return nil
+}
+
+// semverPattern 语义化版本号，允许 v 前缀
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+func validateSemver(input, _ string) error {
+	if !semverPattern.MatchString(strings.TrimSpace(input)) {
+		return localizedError("paramSemverInvalid")
+	}
+	return nil
+}
+
+func validateUser(input, _ string) error {
+	name := strings.TrimSpace(input)
+	if _, err := user.Lookup(name); err != nil {
+		if _, err := user.LookupId(name); err != nil {
+			return localizedError("paramUserNotFound", name)
+		}
+	}
+	return nil
+}
+
+func validateGroup(input, _ string) error {
+	name := strings.TrimSpace(input)
+	if _, err := user.LookupGroup(name); err != nil {
+		if _, err := user.LookupGroupId(name); err != nil {
+			return localizedError("paramGroupNotFound", name)
+		}
+	}
+	return nil
+}
+
+// customValidator 根据配置文件中的自定义类型创建校验器
+func customValidator(name string, paramType model.ParamType) Validator {
+	return func(input, _ string) error {
+		re, err := regexp.Compile("^(?:" + paramType.Pattern + ")$")
+		if err != nil {
+			return localizedError("paramTypePatternInvalid", name, err)
+		}
+		if re.MatchString(input) {
+			return nil
+		}
+		if paramType.Message != "" {
+			return errors.New(paramType.Message)
+		}
+		return localizedError("paramPatternMismatch", paramType.Pattern)
+	}
+}
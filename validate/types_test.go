@@ -0,0 +1,64 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateWritable(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "exists.txt")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		input string
+		ok    bool
+	}{
+		{"existing file", file, true},
+		{"existing dir", dir, true},
+		{"new file in existing dir", filepath.Join(dir, "new.txt"), true},
+		{"missing parent", filepath.Join(dir, "missing", "new.txt"), false},
+		{"parent is a file", filepath.Join(file, "new.txt"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateWritable(tt.input, ""); (err == nil) != tt.ok {
+				t.Errorf("validateWritable(%q) = %v, want ok %v", tt.input, err, tt.ok)
+			}
+		})
+	}
+
+	// 校验不在磁盘上留下或创建任何文件
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("validation touched the directory: %v", entries)
+	}
+}
+
+func TestValidateWritableReadOnly(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only paths")
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "readonly.txt")
+	if err := os.WriteFile(file, []byte("x"), 0444); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateWritable(file, ""); err == nil {
+		t.Error("read-only file should not be writable")
+	}
+}
+
+func TestValidateRegexArg(t *testing.T) {
+	for input, ok := range map[string]bool{"ab-123": true, "abcdef-123": false, "a-123": false} {
+		if err := validateRegex(input, "[a-z]{2,5}-[0-9]{3}"); (err == nil) != ok {
+			t.Errorf("validateRegex(%q) = %v, want ok %v", input, err, ok)
+		}
+	}
+}
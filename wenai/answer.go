@@ -0,0 +1,277 @@
+package wenai
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+	"wen-ai-cli/model"
+	"wen-ai-cli/placeholder"
+)
+
+// answerSection 回答格式中的段落
+type answerSection int
+
+const (
+	sectionOverview     answerSection = iota // 概述，也包括第一个标题之前的内容
+	sectionScript                            // 待执行脚本
+	sectionAnalysis                          // 脚本分析
+	sectionCommonParams                      // 常用参数
+	sectionOther                             // 其他标题下的内容
+)
+
+var (
+	// headingPattern 匹配 Markdown 标题行，允许结尾的 # 号
+	headingPattern = regexp.MustCompile(`^ {0,3}#{1,6}\s+(.+?)(?:\s+#+)?\s*$`)
+	// boldHeadingPattern 匹配整行加粗的伪标题，例如 **概述：**
+	boldHeadingPattern = regexp.MustCompile(`^\s*\*\*([^*]+)\*\*\s*[:：]?\s*$`)
+)
+
+// scriptLanguages 代码块标注为这些语言时视为可运行的脚本，未标注语言的代码块只在待执行脚本段落中视为脚本
+var scriptLanguages = map[string]bool{
+	"code": true, "bash": true, "sh": true, "shell": true, "zsh": true, "ksh": true, "fish": true,
+	"powershell": true, "pwsh": true, "ps1": true, "ps": true, "cmd": true, "bat": true, "batch": true,
+}
+
+// sectionKeywords 按顺序匹配段落标题，"脚本分析"需要先于"脚本"匹配
+var sectionKeywords = []struct {
+	section  answerSection
+	keywords []string
+}{
+	{sectionAnalysis, []string{"脚本分析", "命令分析", "分析", "analysis", "explanation"}},
+	{sectionCommonParams, []string{"常用参数", "扩展参数", "参数", "common param", "options", "parameters"}},
+	{sectionScript, []string{"待执行脚本", "脚本", "命令", "script", "command"}},
+	{sectionOverview, []string{"概述", "overview", "summary"}},
+}
+
+// maxTitleLength 不是标题时，代码块前一行文字作为标题的最大长度
+const maxTitleLength = 40
+
+// codeFence 正在读取的代码块
+type codeFence struct {
+	marker   string   // 开始标记，例如 ``` 或 ~~~~
+	indent   int      // 开始标记前的缩进，代码行去掉最多同样多的缩进
+	language string   // 标注的语言，小写
+	script   bool     // 是否为可运行的脚本
+	lines    []string // 已读取的代码行
+}
+
+// answerParser 逐个分片解析流式回答，识别各种代码块写法和回答格式中的段落。
+// 只处理完整的行，不完整的行暂存等待后续分片
+type answerParser struct {
+	pending  string                             // 尚未遇到换行的内容
+	section  answerSection                      // 当前所在的段落
+	sections map[answerSection]*strings.Builder // 各段落的正文
+	fence    *codeFence                         // 当前所在的代码块，nil 表示不在代码块内
+	title    string                             // 最近的代码块标题，后续代码块没有新标题时沿用
+	heading  string                             // 上一个代码块之后最近的标题
+	lastLine string                             // 上一个代码块之后最近的非空行
+	blocks   []model.ScriptBlock
+}
+
+// Feed 输入一段回答分片
+func (p *answerParser) Feed(text string) {
+	p.pending += text
+	for {
+		index := strings.IndexByte(p.pending, '\n')
+		if index < 0 {
+			return
+		}
+		line := strings.TrimSuffix(p.pending[:index], "\r")
+		p.pending = p.pending[index+1:]
+		p.parseLine(line)
+	}
+}
+
+// Finish 处理剩余内容并返回结构化回答，未闭合的代码块按已读取的内容处理
+func (p *answerParser) Finish() *model.Answer {
+	if p.pending != "" {
+		p.parseLine(strings.TrimSuffix(p.pending, "\r"))
+		p.pending = ""
+	}
+	if p.fence != nil {
+		p.closeFence()
+	}
+	return &model.Answer{
+		Overview:     p.sectionText(sectionOverview),
+		Analysis:     p.sectionText(sectionAnalysis),
+		CommonParams: p.sectionText(sectionCommonParams),
+		Blocks:       p.blocks,
+	}
+}
+
+// parseLine 解析一个完整的行
+func (p *answerParser) parseLine(line string) {
+	if p.fence != nil {
+		p.parseFenceLine(line)
+		return
+	}
+
+	trimmed := strings.TrimLeft(line, " \t")
+	if marker := fenceMarker(trimmed); marker != "" {
+		p.openFence(marker, len(line)-len(trimmed), trimmed[len(marker):])
+		return
+	}
+
+	if title, ok := headingTitle(line); ok {
+		p.section = sectionOf(title)
+		p.heading = cleanTitle(title)
+		p.lastLine = p.heading
+		return
+	}
+	p.appendSection(line)
+	if text := cleanTitle(line); text != "" {
+		p.lastLine = text
+	}
+}
+
+// openFence 开始读取代码块，同一行中闭合的代码块（例如 ```code ls```）直接处理
+func (p *answerParser) openFence(marker string, indent int, rest string) {
+	// 代码块标题取与上一个代码块之间最近的标题，没有时取紧挨着的一行简短说明，再没有时沿用之前的标题
+	if p.heading != "" {
+		p.title = p.heading
+	} else if p.lastLine != "" && utf8.RuneCountInString(p.lastLine) <= maxTitleLength {
+		p.title = p.lastLine
+	}
+	p.heading, p.lastLine = "", ""
+
+	fence := &codeFence{marker: marker, indent: indent}
+	index := strings.Index(rest, "```")
+	singleLine := marker[0] == '`' && index >= 0
+	if singleLine {
+		fence.language, rest = splitLanguage(rest[:index])
+		fence.lines = []string{rest}
+	} else if fields := strings.Fields(rest); len(fields) > 0 {
+		fence.language = strings.ToLower(strings.Trim(fields[0], "{}."))
+	}
+	fence.script = scriptLanguages[fence.language] || (fence.language == "" && p.section == sectionScript)
+	p.fence = fence
+	if singleLine {
+		p.closeFence()
+	}
+}
+
+// parseFenceLine 解析代码块中的一行，遇到结束标记时结束代码块
+func (p *answerParser) parseFenceLine(line string) {
+	trimmed := strings.TrimSpace(line)
+	if closing := fenceMarker(trimmed); closing != "" && closing == trimmed &&
+		closing[0] == p.fence.marker[0] && len(closing) >= len(p.fence.marker) {
+		p.closeFence()
+		return
+	}
+	// 部分模型会把结束标记写在最后一行代码末尾
+	if p.fence.marker == "```" && strings.HasSuffix(trimmed, "```") {
+		p.fence.lines = append(p.fence.lines, strings.TrimSuffix(strings.TrimRight(line, " \t"), "```"))
+		p.closeFence()
+		return
+	}
+	p.fence.lines = append(p.fence.lines, removeIndent(line, p.fence.indent))
+}
+
+// closeFence 结束代码块，可运行的脚本作为代码块记录，跳过空代码块
+func (p *answerParser) closeFence() {
+	fence := p.fence
+	p.fence = nil
+	if !fence.script {
+		return
+	}
+	shellCode := strings.TrimSpace(strings.Join(fence.lines, "\n"))
+	if shellCode == "" {
+		return
+	}
+	p.blocks = append(p.blocks, model.ScriptBlock{
+		Title:     p.title,
+		Language:  fence.language,
+		ShellCode: shellCode,
+		// 解析shellCode中的<下载文件的URL,url>等占位序列化成hideParams
+		NeedFillParams: placeholder.Parse(shellCode),
+	})
+}
+
+// appendSection 将代码块外的一行追加到当前段落
+func (p *answerParser) appendSection(line string) {
+	if p.sections == nil {
+		p.sections = map[answerSection]*strings.Builder{}
+	}
+	builder := p.sections[p.section]
+	if builder == nil {
+		builder = &strings.Builder{}
+		p.sections[p.section] = builder
+	}
+	builder.WriteString(line)
+	builder.WriteByte('\n')
+}
+
+// sectionText 获取段落正文
+func (p *answerParser) sectionText(section answerSection) string {
+	if builder := p.sections[section]; builder != nil {
+		return strings.TrimSpace(builder.String())
+	}
+	return ""
+}
+
+// fenceMarker 获取行首的代码块标记：至少三个 ` 或 ~，不是代码块标记时返回空字符串
+func fenceMarker(line string) string {
+	if line == "" || (line[0] != '`' && line[0] != '~') {
+		return ""
+	}
+	n := 0
+	for n < len(line) && line[n] == line[0] {
+		n++
+	}
+	if n < 3 {
+		return ""
+	}
+	return line[:n]
+}
+
+// splitLanguage 拆分同一行代码块中的语言和代码，第一个单词不是已知语言时整体作为代码
+func splitLanguage(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	first, rest, _ := strings.Cut(text, " ")
+	if language := strings.ToLower(first); scriptLanguages[language] {
+		return language, strings.TrimSpace(rest)
+	}
+	return "", text
+}
+
+// removeIndent 去掉代码行开头最多 indent 个空白字符
+func removeIndent(line string, indent int) string {
+	n := 0
+	for n < indent && n < len(line) && (line[n] == ' ' || line[n] == '\t') {
+		n++
+	}
+	return line[n:]
+}
+
+// headingTitle 获取 Markdown 标题或整行加粗的伪标题的文本
+func headingTitle(line string) (string, bool) {
+	if match := headingPattern.FindStringSubmatch(line); match != nil {
+		return match[1], true
+	}
+	if match := boldHeadingPattern.FindStringSubmatch(line); match != nil {
+		return match[1], true
+	}
+	return "", false
+}
+
+// sectionOf 根据标题判断所属的段落
+func sectionOf(title string) answerSection {
+	title = strings.ToLower(title)
+	for _, candidate := range sectionKeywords {
+		for _, keyword := range candidate.keywords {
+			if strings.Contains(title, keyword) {
+				return candidate.section
+			}
+		}
+	}
+	return sectionOther
+}
+
+// cleanTitle 去掉标题中的强调符号、列表符号和结尾的冒号
+func cleanTitle(title string) string {
+	title = strings.TrimSpace(title)
+	title = strings.TrimLeft(title, "-*> ")
+	title = strings.ReplaceAll(title, "**", "")
+	title = strings.TrimRight(title, "：: ")
+	return strings.TrimSpace(title)
+}
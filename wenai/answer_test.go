@@ -0,0 +1,150 @@
+package wenai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"wen-ai-cli/model"
+)
+
+// wantBlock 期望的代码块，参数只比较名称
+type wantBlock struct {
+	title    string
+	language string
+	code     string
+	params   []string
+}
+
+// parseAnswer 按 size 字节切分回答后逐片输入解析器，size 为 0 时整体输入
+func parseAnswer(text string, size int) *model.Answer {
+	parser := &answerParser{}
+	if size <= 0 {
+		size = len(text) + 1
+	}
+	for start := 0; start < len(text); start += size {
+		parser.Feed(text[start:min(start+size, len(text))])
+	}
+	return parser.Finish()
+}
+
+func TestAnswerParser(t *testing.T) {
+	tests := []struct {
+		name         string
+		answer       string
+		blocks       []wantBlock
+		overview     string
+		analysis     string
+		commonParams string
+	}{
+		{
+			name: "all four sections",
+			answer: "### 概述\n查看当前目录下的文件\n\n" +
+				"### 待执行脚本\n```code\nls -la <目录,dir,.>\n```\n\n" +
+				"### 脚本分析\n- `ls` 列出文件\n\n" +
+				"### 常用参数\n- `-h` 以易读的单位显示大小\n",
+			blocks:       []wantBlock{{"待执行脚本", "code", "ls -la <目录,dir,.>", []string{"目录"}}},
+			overview:     "查看当前目录下的文件",
+			analysis:     "- `ls` 列出文件",
+			commonParams: "- `-h` 以易读的单位显示大小",
+		},
+		{
+			name:     "english answer with bash",
+			answer:   "## Overview\nList files.\n\n## Script\n```bash\nls -la\n```\n\n## Analysis\nLists all files.\n",
+			blocks:   []wantBlock{{"Script", "bash", "ls -la", nil}},
+			overview: "List files.",
+			analysis: "Lists all files.",
+		},
+		{
+			name:     "text before the first heading is the overview",
+			answer:   "下面是命令：\n```sh\ndf -h\n```\n",
+			blocks:   []wantBlock{{"下面是命令", "sh", "df -h", nil}},
+			overview: "下面是命令：",
+		},
+		{
+			name:   "indented sh block and a placeholder with spaces",
+			answer: "### 脚本\n1. 创建用户：\n   ```sh\n   useradd <имя пользователя, string>\n     -m\n   ```\n",
+			blocks: []wantBlock{{"脚本", "sh", "useradd <имя пользователя, string>\n  -m", []string{"имя пользователя"}}},
+		},
+		{
+			name:   "powershell with full-width commas",
+			answer: "### 待执行脚本\n```powershell\nGet-ChildItem -Path <目录，dir，C:\\>\n```\n",
+			blocks: []wantBlock{{"待执行脚本", "powershell", "Get-ChildItem -Path <目录，dir，C:\\>", []string{"目录"}}},
+		},
+		{
+			name:   "single-line code fence",
+			answer: "### 待执行脚本\n```code echo inline```\n",
+			blocks: []wantBlock{{"待执行脚本", "code", "echo inline", nil}},
+		},
+		{
+			name:   "untagged block only counts in the script section",
+			answer: "### 概述\n```\nnot a script\n```\n### 待执行脚本\n```\nuptime\n```\n",
+			blocks: []wantBlock{{"待执行脚本", "", "uptime", nil}},
+		},
+		{
+			name:   "tilde fence and json block",
+			answer: "### 示例配置\n```json\n{\"a\": 1}\n```\n### 待执行脚本\n~~~bash\necho ~~~ ok\n~~~\n",
+			blocks: []wantBlock{{"待执行脚本", "bash", "echo ~~~ ok", nil}},
+		},
+		{
+			name:   "longer fence with a nested fence",
+			answer: "### 待执行脚本\n````bash\ncat <<'EOF' > README.md\n```\ncode\n```\nEOF\n````\n",
+			blocks: []wantBlock{{"待执行脚本", "bash", "cat <<'EOF' > README.md\n```\ncode\n```\nEOF", nil}},
+		},
+		{
+			name:     "closing fence at the end of a line",
+			answer:   "### 待执行脚本\n```bash\nwhoami\nid```\n\n### 脚本分析\n查看当前用户\n",
+			blocks:   []wantBlock{{"待执行脚本", "bash", "whoami\nid", nil}},
+			analysis: "查看当前用户",
+		},
+		{
+			name:     "CRLF line endings and bold headings",
+			answer:   "**概述：**\r\n查看磁盘\r\n\r\n**待执行脚本：**\r\n```code\r\ndf -h\r\n```\r\n",
+			blocks:   []wantBlock{{"待执行脚本", "code", "df -h", nil}},
+			overview: "查看磁盘",
+		},
+		{
+			name: "multiple blocks keep their own titles",
+			answer: "### 待执行脚本\n#### 1. 安装\n```bash\napt install -y <软件包,string,nginx>\n```\n" +
+				"启动服务\n```bash\nsystemctl start <服务名,string,nginx>\n```\n```bash\nsystemctl status nginx\n```\n",
+			blocks: []wantBlock{
+				{"1. 安装", "bash", "apt install -y <软件包,string,nginx>", []string{"软件包"}},
+				{"启动服务", "bash", "systemctl start <服务名,string,nginx>", []string{"服务名"}},
+				{"启动服务", "bash", "systemctl status nginx", nil},
+			},
+		},
+		{
+			name:   "unclosed fence at the end of the answer",
+			answer: "### 待执行脚本\n```bash\nls",
+			blocks: []wantBlock{{"待执行脚本", "bash", "ls", nil}},
+		},
+		{
+			name:     "no blocks",
+			answer:   "### 概述\n无法给出脚本",
+			overview: "无法给出脚本",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 同一回答按不同大小切分时结果一致，覆盖代码块标记被切分到多个分片的情况
+			for _, size := range []int{0, 1, 2, 3, 7} {
+				answer := parseAnswer(tt.answer, size)
+				var got []wantBlock
+				for _, block := range answer.Blocks {
+					var params []string
+					for _, param := range block.NeedFillParams {
+						params = append(params, param.Param)
+					}
+					got = append(got, wantBlock{block.Title, block.Language, block.ShellCode, params})
+				}
+				if !reflect.DeepEqual(got, tt.blocks) {
+					t.Fatalf("chunk size %d: blocks = %#v\nwant %#v", size, got, tt.blocks)
+				}
+				sections := []string{answer.Overview, answer.Analysis, answer.CommonParams}
+				want := []string{tt.overview, tt.analysis, tt.commonParams}
+				if strings.Join(sections, "|") != strings.Join(want, "|") {
+					t.Fatalf("chunk size %d: sections = %q, want %q", size, sections, want)
+				}
+			}
+		})
+	}
+}
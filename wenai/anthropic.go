@@ -0,0 +1,31 @@
+package wenai
+
+import (
+	"context"
+	"net/http"
+	wenmodel "wen-ai-cli/model"
+	"wen-ai-cli/wenai/anthropic"
+
+	"github.com/cloudwego/eino/components/model"
+)
+
+// newAnthropicChatModel 创建 Anthropic Messages API 的聊天模型
+func newAnthropicChatModel(ctx context.Context, profile *wenmodel.Profile, httpClient *http.Client) (model.BaseChatModel, error) {
+	// 生成参数中的 maxTokens 优先于 anthropic 专有选项
+	maxTokens := profile.Anthropic.MaxTokens
+	if profile.Generation.MaxTokens != nil {
+		maxTokens = *profile.Generation.MaxTokens
+	}
+	return anthropic.NewChatModel(ctx, &anthropic.Config{
+		BaseURL:    profile.BaseURL,
+		APIKey:     profile.APIKey,
+		Model:      profile.Model,
+		Version:    profile.Anthropic.Version,
+		MaxTokens:  maxTokens,
+		HTTPClient: httpClient,
+		// 生成参数，Messages API 不支持 seed
+		Temperature: profile.Generation.Temperature,
+		TopP:        profile.Generation.TopP,
+		Stop:        profile.Generation.Stop,
+	})
+}
@@ -0,0 +1,384 @@
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// DefaultBaseURL Anthropic 官方接口地址
+	DefaultBaseURL = "https://api.anthropic.com"
+	// DefaultVersion 默认的 anthropic-version 请求头
+	DefaultVersion = "2023-06-01"
+	// DefaultMaxTokens Messages API 要求必须指定 max_tokens
+	DefaultMaxTokens = 4096
+)
+
+var _ model.BaseChatModel = (*ChatModel)(nil)
+
+// Config Anthropic 聊天模型配置
+type Config struct {
+	BaseURL    string       // 服务地址，可以是网关地址
+	APIKey     string       // 通过 x-api-key 请求头发送
+	Model      string       // 模型名称
+	Version    string       // anthropic-version 请求头，为空时使用 DefaultVersion
+	MaxTokens  int          // 最大输出 token 数，为空时使用 DefaultMaxTokens
+	HTTPClient *http.Client // 自定义 HTTP 客户端，为空时使用 http.DefaultClient
+
+	// 生成参数，调用时传入的选项优先
+	Temperature *float32
+	TopP        *float32
+	Stop        []string
+}
+
+// APIError Messages API 返回的错误
+type APIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("anthropic error, status code: %d, type: %s, message: %s", e.StatusCode, e.Type, e.Message)
+}
+
+// ChatModel 基于 Anthropic Messages API 的聊天模型
+type ChatModel struct {
+	conf *Config
+	cli  *http.Client
+}
+
+// NewChatModel 创建 Anthropic 聊天模型
+func NewChatModel(ctx context.Context, conf *Config) (*ChatModel, error) {
+	if conf == nil || conf.Model == "" {
+		return nil, errors.New("anthropic: model is required")
+	}
+	return newChatModel(conf), nil
+}
+
+// newChatModel 填充配置默认值并创建聊天模型，不校验模型名称
+func newChatModel(conf *Config) *ChatModel {
+	c := *conf
+	if c.BaseURL == "" {
+		c.BaseURL = DefaultBaseURL
+	}
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	if c.Version == "" {
+		c.Version = DefaultVersion
+	}
+	if c.MaxTokens <= 0 {
+		c.MaxTokens = DefaultMaxTokens
+	}
+	cli := c.HTTPClient
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	return &ChatModel{conf: &c, cli: cli}
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// tool Messages API 的工具定义
+type tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+type messagesRequest struct {
+	Model         string    `json:"model"`
+	System        string    `json:"system,omitempty"`
+	Messages      []message `json:"messages"`
+	MaxTokens     int       `json:"max_tokens"`
+	Stream        bool      `json:"stream,omitempty"`
+	Temperature   *float32  `json:"temperature,omitempty"`
+	TopP          *float32  `json:"top_p,omitempty"`
+	StopSequences []string  `json:"stop_sequences,omitempty"`
+	Tools         []tool    `json:"tools,omitempty"`
+}
+
+type contentBlock struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text"`
+	Thinking string          `json:"thinking"`
+	ID       string          `json:"id"`    // tool_use 的调用标识
+	Name     string          `json:"name"`  // tool_use 的工具名称
+	Input    json.RawMessage `json:"input"` // tool_use 的调用参数
+}
+
+type usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type messagesResponse struct {
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      usage          `json:"usage"`
+}
+
+// streamEvent SSE 事件的 data 部分，不同事件类型只使用其中一部分字段
+type streamEvent struct {
+	Type         string           `json:"type"`
+	Message      messagesResponse `json:"message"`
+	Index        int              `json:"index"`
+	ContentBlock contentBlock     `json:"content_block"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		Thinking    string `json:"thinking"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage usage `json:"usage"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Generate 非流式生成回复
+func (cm *ChatModel) Generate(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	resp, err := cm.doMessages(ctx, in, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out messagesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("anthropic: decode response failed: %w", err)
+	}
+	msg := &schema.Message{Role: schema.Assistant}
+	for _, block := range out.Content {
+		switch block.Type {
+		case "text":
+			msg.Content += block.Text
+		case "thinking":
+			msg.ReasoningContent += block.Thinking
+		case "tool_use":
+			msg.ToolCalls = append(msg.ToolCalls, toolCall(len(msg.ToolCalls), block.ID, block.Name, string(block.Input)))
+		}
+	}
+	msg.ResponseMeta = toResponseMeta(out.StopReason, out.Usage)
+	return msg, nil
+}
+
+// Stream 流式生成回复，解析 Messages API 的 SSE 事件
+func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	resp, err := cm.doMessages(ctx, in, true, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer resp.Body.Close()
+		defer sw.Close()
+
+		// 输入 token 在 message_start 中返回，输出 token 在 message_delta 中返回
+		var inputTokens int
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			var event streamEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(line[len("data:"):])), &event); err != nil {
+				sw.Send(nil, fmt.Errorf("anthropic: decode stream event failed: %w", err))
+				return
+			}
+
+			var msg *schema.Message
+			switch event.Type {
+			case "message_start":
+				inputTokens = event.Message.Usage.InputTokens
+			case "content_block_start":
+				// 工具调用的参数在之后的 input_json_delta 中分段返回，按内容块序号合并
+				if block := event.ContentBlock; block.Type == "tool_use" {
+					msg = &schema.Message{Role: schema.Assistant,
+						ToolCalls: []schema.ToolCall{toolCall(event.Index, block.ID, block.Name, "")}}
+				}
+			case "content_block_delta":
+				switch event.Delta.Type {
+				case "text_delta":
+					msg = &schema.Message{Role: schema.Assistant, Content: event.Delta.Text}
+				case "thinking_delta":
+					msg = &schema.Message{Role: schema.Assistant, ReasoningContent: event.Delta.Thinking}
+				case "input_json_delta":
+					msg = &schema.Message{Role: schema.Assistant,
+						ToolCalls: []schema.ToolCall{toolCall(event.Index, "", "", event.Delta.PartialJSON)}}
+				}
+			case "message_delta":
+				msg = &schema.Message{Role: schema.Assistant}
+				msg.ResponseMeta = toResponseMeta(event.Delta.StopReason, usage{
+					InputTokens:  inputTokens,
+					OutputTokens: event.Usage.OutputTokens,
+				})
+			case "error":
+				sw.Send(nil, &APIError{StatusCode: resp.StatusCode, Type: event.Error.Type, Message: event.Error.Message})
+				return
+			case "message_stop":
+				return
+			}
+			if msg == nil {
+				continue
+			}
+			if closed := sw.Send(msg, nil); closed {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			sw.Send(nil, fmt.Errorf("anthropic: read stream failed: %w", err))
+		}
+	}()
+	return sr, nil
+}
+
+// doMessages 发送 /v1/messages 请求，system 消息合并到顶层 system 字段
+func (cm *ChatModel) doMessages(ctx context.Context, in []*schema.Message, stream bool, opts ...model.Option) (*http.Response, error) {
+	options := model.GetCommonOptions(&model.Options{
+		Model:       &cm.conf.Model,
+		MaxTokens:   &cm.conf.MaxTokens,
+		Temperature: cm.conf.Temperature,
+		TopP:        cm.conf.TopP,
+		Stop:        cm.conf.Stop,
+	}, opts...)
+
+	req := &messagesRequest{
+		Model:         *options.Model,
+		MaxTokens:     *options.MaxTokens,
+		Stream:        stream,
+		Temperature:   options.Temperature,
+		TopP:          options.TopP,
+		StopSequences: options.Stop,
+	}
+	var systems []string
+	for _, msg := range in {
+		switch msg.Role {
+		case schema.System:
+			systems = append(systems, msg.Content)
+		case schema.Assistant:
+			req.Messages = append(req.Messages, message{Role: "assistant", Content: msg.Content})
+		default:
+			req.Messages = append(req.Messages, message{Role: "user", Content: msg.Content})
+		}
+	}
+	req.System = strings.Join(systems, "\n\n")
+	tools, err := toTools(options.Tools)
+	if err != nil {
+		return nil, err
+	}
+	req.Tools = tools
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cm.messagesURL(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	cm.setAuthHeaders(httpReq)
+
+	resp, err := cm.cli.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+	return resp, nil
+}
+
+// messagesURL 获取 Messages API 地址
+func (cm *ChatModel) messagesURL() string {
+	return cm.apiURL("/messages")
+}
+
+// apiURL 拼接 /v1 下的接口地址，兼容以 /v1 结尾的网关地址
+func (cm *ChatModel) apiURL(path string) string {
+	if strings.HasSuffix(cm.conf.BaseURL, "/v1") {
+		return cm.conf.BaseURL + path
+	}
+	return cm.conf.BaseURL + "/v1" + path
+}
+
+// setAuthHeaders 设置认证和版本请求头
+func (cm *ChatModel) setAuthHeaders(req *http.Request) {
+	req.Header.Set("x-api-key", cm.conf.APIKey)
+	req.Header.Set("anthropic-version", cm.conf.Version)
+}
+
+// newAPIError 从错误响应中读取 Messages API 的错误信息
+func newAPIError(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(raw))}
+	if json.Unmarshal(raw, &body) == nil && body.Error.Message != "" {
+		apiErr.Type = body.Error.Type
+		apiErr.Message = body.Error.Message
+	}
+	return apiErr
+}
+
+// toTools 将 eino 的工具定义转换为 Messages API 的工具，参数使用 JSON Schema 描述
+func toTools(infos []*schema.ToolInfo) ([]tool, error) {
+	var tools []tool
+	for _, info := range infos {
+		inputSchema, err := info.ParamsOneOf.ToJSONSchema()
+		if err != nil {
+			return nil, fmt.Errorf("anthropic: convert tool %s failed: %w", info.Name, err)
+		}
+		t := tool{Name: info.Name, Description: info.Desc, InputSchema: inputSchema}
+		if inputSchema == nil {
+			t.InputSchema = map[string]any{"type": "object"}
+		}
+		tools = append(tools, t)
+	}
+	return tools, nil
+}
+
+// toolCall 创建 eino 的工具调用，流式分片中同一工具调用使用相同的 index
+func toolCall(index int, id, name, arguments string) schema.ToolCall {
+	return schema.ToolCall{
+		Index:    &index,
+		ID:       id,
+		Type:     "function",
+		Function: schema.FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+// toResponseMeta 将结束原因和 token 用量转换为 eino 的响应元信息
+func toResponseMeta(stopReason string, u usage) *schema.ResponseMeta {
+	return &schema.ResponseMeta{
+		FinishReason: stopReason,
+		Usage: &schema.TokenUsage{
+			PromptTokens:     u.InputTokens,
+			CompletionTokens: u.OutputTokens,
+			TotalTokens:      u.InputTokens + u.OutputTokens,
+		},
+	}
+}
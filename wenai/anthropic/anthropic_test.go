@@ -0,0 +1,160 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+var testTool = &schema.ToolInfo{
+	Name: "propose_command",
+	Desc: "submit the script",
+	ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+		"script": {Type: schema.String, Required: true},
+	}),
+}
+
+// newTestServer 启动模拟 /v1/messages 的服务，返回 body 并记录收到的请求
+func newTestServer(t *testing.T, contentType, body string, got *map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", contentType)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// checkTools 校验请求中按 Messages API 格式传入了工具定义
+func checkTools(t *testing.T, req map[string]any) {
+	t.Helper()
+	tools, _ := req["tools"].([]any)
+	if len(tools) != 1 {
+		t.Fatalf("tools = %v, want one tool", req["tools"])
+	}
+	tool := tools[0].(map[string]any)
+	inputSchema, _ := tool["input_schema"].(map[string]any)
+	if tool["name"] != "propose_command" || tool["description"] != "submit the script" || inputSchema["type"] != "object" {
+		t.Errorf("unexpected tool: %v", tool)
+	}
+	if _, ok := inputSchema["properties"].(map[string]any)["script"]; !ok {
+		t.Errorf("input_schema has no script property: %v", inputSchema)
+	}
+}
+
+func TestStreamToolUse(t *testing.T) {
+	var req map[string]any
+	events := []string{
+		`{"type":"message_start","message":{"usage":{"input_tokens":20,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Listing files."}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_01","name":"propose_command","input":{}}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"script\": \"ls"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":" -la\"}"}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":15}}`,
+		`{"type":"message_stop"}`,
+	}
+	body := ""
+	for _, event := range events {
+		body += "event: x\ndata: " + event + "\n\n"
+	}
+	server := newTestServer(t, "text/event-stream", body, &req)
+
+	cm, err := NewChatModel(context.Background(), &Config{BaseURL: server.URL, Model: "claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sr, err := cm.Stream(context.Background(), []*schema.Message{schema.SystemMessage("sys"), schema.UserMessage("hi")},
+		model.WithTools([]*schema.ToolInfo{testTool}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	sr.Close()
+
+	merged, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Content != "Listing files." {
+		t.Errorf("content = %q", merged.Content)
+	}
+	if len(merged.ToolCalls) != 1 {
+		t.Fatalf("tool calls = %+v, want one", merged.ToolCalls)
+	}
+	call := merged.ToolCalls[0]
+	if call.ID != "toolu_01" || call.Function.Name != "propose_command" || call.Function.Arguments != `{"script": "ls -la"}` {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+	if usage := merged.ResponseMeta.Usage; usage.PromptTokens != 20 || usage.CompletionTokens != 15 {
+		t.Errorf("usage = %+v", usage)
+	}
+	if req["system"] != "sys" {
+		t.Errorf("system = %v", req["system"])
+	}
+	checkTools(t, req)
+}
+
+func TestGenerateToolUse(t *testing.T) {
+	var req map[string]any
+	server := newTestServer(t, "application/json", `{"content":[
+		{"type":"text","text":"Listing files."},
+		{"type":"tool_use","id":"toolu_01","name":"propose_command","input":{"script":"ls -la"}}
+	],"stop_reason":"tool_use","usage":{"input_tokens":20,"output_tokens":15}}`, &req)
+
+	cm, err := NewChatModel(context.Background(), &Config{BaseURL: server.URL + "/v1", Model: "claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := cm.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")},
+		model.WithTools([]*schema.ToolInfo{testTool}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Function.Arguments != `{"script":"ls -la"}` {
+		t.Errorf("tool calls = %+v", msg.ToolCalls)
+	}
+	checkTools(t, req)
+}
+
+func TestNoTools(t *testing.T) {
+	var req map[string]any
+	server := newTestServer(t, "application/json", `{"content":[{"type":"text","text":"ok"}]}`, &req)
+	cm, err := NewChatModel(context.Background(), &Config{BaseURL: server.URL, Model: "claude"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cm.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := req["tools"]; ok {
+		t.Errorf("tools should be omitted: %v", req["tools"])
+	}
+}
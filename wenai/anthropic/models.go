@@ -0,0 +1,40 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ModelInfo /v1/models 返回的模型信息
+type ModelInfo struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+}
+
+// ListModels 通过 /v1/models 获取可用模型列表，配置中的模型名称可以为空
+func ListModels(ctx context.Context, conf *Config) ([]ModelInfo, error) {
+	cm := newChatModel(conf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cm.apiURL("/models?limit=1000"), nil)
+	if err != nil {
+		return nil, err
+	}
+	cm.setAuthHeaders(req)
+	resp, err := cm.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, newAPIError(resp)
+	}
+
+	var list struct {
+		Data []ModelInfo `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("anthropic: decode models failed: %w", err)
+	}
+	return list.Data, nil
+}
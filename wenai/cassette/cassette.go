@@ -0,0 +1,81 @@
+// Package cassette 提供大模型调用的录制与回放，录制的请求消息和流式分片保存在 cassette 文件中，
+// 回放时不需要连接真实的大模型服务，便于离线演示和测试 once、chat、man 流程
+package cassette
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Interaction 一次录制的大模型调用
+type Interaction struct {
+	Key     string            `json:"key"`             // 请求消息的摘要，回放时用于匹配
+	Request []*schema.Message `json:"request"`         // 请求消息
+	Chunks  []*schema.Message `json:"chunks"`          // 按顺序收到的流式分片
+	Error   string            `json:"error,omitempty"` // 流式读取中途失败时的错误信息
+}
+
+// Cassette 录制文件内容
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// 同一进程内对 cassette 文件的读写需要串行，chat 模式每轮都会新建模型
+var fileLock sync.Mutex
+
+// RequestKey 计算请求消息的摘要
+func RequestKey(messages []*schema.Message) string {
+	h := sha256.New()
+	for _, msg := range messages {
+		h.Write([]byte(msg.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(msg.Content))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Load 读取 cassette 文件
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, err
+	}
+	return &cassette, nil
+}
+
+// appendInteraction 将一次调用追加到 cassette 文件，文件不存在时创建
+func appendInteraction(path string, interaction Interaction) error {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+
+	cassette, err := Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		cassette = &Cassette{}
+	} else if err != nil {
+		return err
+	}
+	cassette.Interactions = append(cassette.Interactions, interaction)
+
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,117 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"wen-ai-cli/logger"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Player 按 cassette 文件回放录制的回答，不连接真实的大模型服务
+type Player struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// 同一 cassette 文件在进程内共享回放进度，chat 模式多轮对话才能依次回放
+var (
+	playersLock sync.Mutex
+	players     = map[string]*Player{}
+)
+
+// OpenPlayer 打开 cassette 文件对应的回放模型
+func OpenPlayer(path string) (*Player, error) {
+	playersLock.Lock()
+	defer playersLock.Unlock()
+
+	if player, ok := players[path]; ok {
+		return player, nil
+	}
+	cassette, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	player := &Player{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}
+	players[path] = player
+	return player, nil
+}
+
+// Generate 回放录制的回答并合并为完整消息
+func (p *Player) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	interaction, err := p.next(input)
+	if err != nil {
+		return nil, err
+	}
+	if interaction.Error != "" {
+		return nil, errors.New(interaction.Error)
+	}
+	return schema.ConcatMessages(interaction.Chunks)
+}
+
+// Stream 按录制顺序回放流式分片，录制时中途失败的调用在最后一个分片之后返回同样的错误
+func (p *Player) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	interaction, err := p.next(input)
+	if err != nil {
+		return nil, err
+	}
+	if interaction.Error == "" {
+		return schema.StreamReaderFromArray(interaction.Chunks), nil
+	}
+	out, writer := schema.Pipe[*schema.Message](len(interaction.Chunks) + 1)
+	for _, chunk := range interaction.Chunks {
+		writer.Send(chunk, nil)
+	}
+	writer.Send(nil, errors.New(interaction.Error))
+	writer.Close()
+	return out, nil
+}
+
+// ErrRequestMismatch 回放时没有与请求的问题一致的录制
+var ErrRequestMismatch = errors.New("cassette has no interaction matching this request")
+
+// next 取出与请求匹配的下一条录制。请求消息不完全一致时（例如系统提示词中的工作目录不同），
+// 按录制顺序回放问题（最后一条消息）相同的录制，问题也不同时返回 ErrRequestMismatch
+func (p *Player) next(input []*schema.Message) (*Interaction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := RequestKey(input)
+	for i := range p.interactions {
+		if !p.used[i] && p.interactions[i].Key == key {
+			p.used[i] = true
+			return &p.interactions[i], nil
+		}
+	}
+	remaining := false
+	for i := range p.interactions {
+		if p.used[i] {
+			continue
+		}
+		remaining = true
+		if lastContent(p.interactions[i].Request) == lastContent(input) {
+			logger.Debugf("cassette request mismatch, replay interaction %d with the same question", i)
+			p.used[i] = true
+			return &p.interactions[i], nil
+		}
+	}
+	if remaining {
+		return nil, ErrRequestMismatch
+	}
+	return nil, fmt.Errorf("cassette has no interaction left for this request")
+}
+
+// lastContent 获取最后一条消息的内容，即本次提问
+func lastContent(messages []*schema.Message) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	return messages[len(messages)-1].Content
+}
@@ -0,0 +1,75 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"io"
+	"wen-ai-cli/logger"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Recorder 包装真实的聊天模型，将每次调用的请求和回答追加到 cassette 文件
+type Recorder struct {
+	model.BaseChatModel
+	path string
+}
+
+// NewRecorder 创建录制模型
+func NewRecorder(chatModel model.BaseChatModel, path string) *Recorder {
+	return &Recorder{BaseChatModel: chatModel, path: path}
+}
+
+// Generate 调用真实模型并录制完整回答
+func (r *Recorder) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	message, err := r.BaseChatModel.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	r.save(Interaction{
+		Key:     RequestKey(input),
+		Request: input,
+		Chunks:  []*schema.Message{message},
+	})
+	return message, nil
+}
+
+// Stream 调用真实模型，边转发流式分片边录制，流结束后写入 cassette 文件
+func (r *Recorder) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	sr, err := r.BaseChatModel.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out, writer := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer sr.Close()
+		defer writer.Close()
+
+		interaction := Interaction{Key: RequestKey(input), Request: input}
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				interaction.Error = err.Error()
+				writer.Send(nil, err)
+				break
+			}
+			interaction.Chunks = append(interaction.Chunks, chunk)
+			// 下游已关闭时停止录制，未读完的回答不写入 cassette
+			if closed := writer.Send(chunk, nil); closed {
+				return
+			}
+		}
+		r.save(interaction)
+	}()
+	return out, nil
+}
+
+func (r *Recorder) save(interaction Interaction) {
+	if err := appendInteraction(r.path, interaction); err != nil {
+		logger.Warnf("record cassette failed: %v", err)
+	}
+}
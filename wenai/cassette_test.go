@@ -0,0 +1,152 @@
+package wenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	wenmodel "wen-ai-cli/model"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/wenai/cassette"
+	"wen-ai-cli/wenai/chat"
+	"wen-ai-cli/wenai/manual"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// writeCassette 将请求和分片写入临时 cassette 文件并开启回放
+func writeCassette(t *testing.T, interactions ...cassette.Interaction) {
+	t.Helper()
+	for i := range interactions {
+		interactions[i].Key = cassette.RequestKey(interactions[i].Request)
+	}
+	data, err := json.Marshal(cassette.Cassette{Interactions: interactions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	setup.SetCassette("", path)
+	t.Cleanup(func() { setup.SetCassette("", "") })
+}
+
+// chunks 将回答拆分为流式分片，最后一个分片带有用量
+func chunks(parts ...string) []*schema.Message {
+	var messages []*schema.Message
+	for _, part := range parts {
+		messages = append(messages, schema.AssistantMessage(part, nil))
+	}
+	messages[len(messages)-1].ResponseMeta = &schema.ResponseMeta{Usage: &schema.TokenUsage{PromptTokens: 10, CompletionTokens: 4, TotalTokens: 14}}
+	return messages
+}
+
+func TestReplayCassette(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	history := []*schema.Message{
+		schema.UserMessage("查看当前目录下的文件"),
+		schema.AssistantMessage("```code\nls -la\n```", nil),
+	}
+	tests := []struct {
+		name      string
+		messages  []*schema.Message
+		parts     []string
+		content   string
+		shellCode string
+	}{
+		{
+			name:      "once",
+			messages:  chat.CreateOnceMessagesFromTemplate("查看当前目录下的文件", true, true, true, true),
+			parts:     []string{"### 待执行脚本\n``", "`code\nls -la\n", "```\n"},
+			content:   "### 待执行脚本\n```code\nls -la\n```\n",
+			shellCode: "ls -la",
+		},
+		{
+			name:      "chat",
+			messages:  chat.CreateMoreMessagesFromTemplate("只看隐藏文件", history, true, true, true, true),
+			parts:     []string{"### 待执行脚本\n```code\nls -d .*", "\n```\n"},
+			content:   "### 待执行脚本\n```code\nls -d .*\n```\n",
+			shellCode: "ls -d .*",
+		},
+		{
+			name:     "man",
+			messages: manual.CreateOnceMessagesFromTemplate("ls", "ls", true, true, true, true),
+			parts:    []string{"## 名称\n", "ls - 列出目录内容\n"},
+			content:  "## 名称\nls - 列出目录内容\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeCassette(t, cassette.Interaction{Request: tt.messages, Chunks: chunks(tt.parts...)})
+
+			llm, err := NewChatModel(context.Background(), &wenmodel.Profile{Model: "recorded"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sr, err := Stream(context.Background(), llm, tt.messages)
+			if err != nil {
+				t.Fatal(err)
+			}
+			message, hiddenParams, err := ReportStream(context.Background(), sr, ReportOptions{ModelName: "recorded"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if message.Content != tt.content {
+				t.Errorf("content = %q, want %q", message.Content, tt.content)
+			}
+			if usage := message.ResponseMeta.Usage; usage == nil || usage.TotalTokens != 14 {
+				t.Errorf("usage = %+v, want the recorded usage", usage)
+			}
+			if tt.shellCode == "" {
+				if len(hiddenParams.Blocks) != 0 {
+					t.Errorf("blocks = %+v, want none", hiddenParams.Blocks)
+				}
+				return
+			}
+			if hiddenParams.ShellCode != tt.shellCode {
+				t.Errorf("shell code = %q, want %q", hiddenParams.ShellCode, tt.shellCode)
+			}
+		})
+	}
+}
+
+func TestReplayCassetteSameQuestion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	recorded := []*schema.Message{schema.SystemMessage("工作目录：/home/a"), schema.UserMessage("查看当前目录下的文件")}
+	writeCassette(t, cassette.Interaction{Request: recorded, Chunks: chunks("```code\nls\n```")})
+
+	llm, err := NewChatModel(context.Background(), &wenmodel.Profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 系统提示词中的工作目录不同，提问相同时仍然回放
+	input := []*schema.Message{schema.SystemMessage("工作目录：/home/b"), schema.UserMessage("查看当前目录下的文件")}
+	message, err := Generate(context.Background(), llm, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Content != "```code\nls\n```" {
+		t.Errorf("content = %q", message.Content)
+	}
+}
+
+func TestReplayCassetteMismatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	recorded := chat.CreateOnceMessagesFromTemplate("查看当前目录下的文件", true, true, true, true)
+	writeCassette(t, cassette.Interaction{Request: recorded, Chunks: chunks("```code\nls\n```")})
+
+	llm, err := NewChatModel(context.Background(), &wenmodel.Profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Stream(context.Background(), llm, chat.CreateOnceMessagesFromTemplate("删除临时文件", true, true, true, true))
+	if !errors.Is(err, cassette.ErrRequestMismatch) {
+		t.Fatalf("err = %v, want ErrRequestMismatch", err)
+	}
+	if kind := ClassifyError(err).Kind; kind != ErrorKindUnknown {
+		t.Errorf("kind = %d, want ErrorKindUnknown", kind)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"wen-ai-cli/common"
 	"wen-ai-cli/logger"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/validate"
 
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
@@ -69,8 +71,8 @@ var workFlowSteps = []struct {
 
 var answerDescription = `- 回答说明: 
 	1. 最佳脚本必须使用<code>和</code>包裹。	
-	2. <code>标签最佳脚本中，如需用户补充参数值必须使用 < 和 > 符号包裹，且格式为: <参数解释,此参数类型[可选：url,string,number]>。
-	3. code内容示例：<code> curl -o <本地文件名称,string> <下载文件的URL,url> </code>
+	2. <code>标签最佳脚本中，如需用户补充参数值必须使用 < 和 > 符号包裹，且格式为: <参数解释,此参数类型[,默认值[,参数说明]]>。参数类型可选：{paramTypes}，其中 int 可以限定范围，例如 int:1..100，regex 可以给出需要匹配的正则表达式，例如 regex:[a-z0-9-]+ 或 regex:[a-z]{2,5}（表达式中除花括号内以外不能包含逗号），file、dir 表示已存在的文件或目录，writable 表示可写入的路径，secret 表示密码、令牌等敏感值（不要填写默认值），或 enum:选项1|选项2|选项3 表示只能从给出的选项中选择；有常用的值时填写默认值，没有时省略默认值，需要写说明而没有默认值时默认值留空。
+	3. code内容示例：<code> curl -o <本地文件名称,string,index.html> <下载文件的URL,url> </code>，<code> kubectl config use-context <目标环境,enum:dev|staging|prod,staging> </code>，<code> tar -czf <压缩包名称,string,,不需要写扩展名>.tar.gz . </code>，<code> mysql -u root -p<数据库密码,secret> </code>
 	4. <placeholder></placeholder>标签中的内容为占位说明，必须按照占位说明进行替换，且不保留<placeholder>标签。
 	5. 如果用户与你存在多轮对话，你的历史回答可能是错误的，或者回答格式不符合参考格式标准，请结合历史对话内容和最新用户意图，在能够解答用户问题的前提下，必须使用完整的正确的参考格式回答。`
 
@@ -142,6 +144,10 @@ func getWorkPlatform(enablePlatformPerception bool) string {
 	if err != nil {
 		logger.Errorf("get shell platform failed: %v\n", err)
 	}
+	// 配置了执行脚本的 shell 时，按实际执行的 shell 生成命令
+	if configured := setup.GetConfig().Shell; configured != "" {
+		shellPlatform = configured
+	}
 	workPlatform = strings.Replace(workPlatform, "{systemInfo}", systemInfo, -1)
 	workPlatform = strings.Replace(workPlatform, "{shellPlatform}", shellPlatform, -1)
 	return workPlatform
@@ -164,6 +170,11 @@ func getWorkUserAndDir(enableWorkUserAndDir bool) string {
 	return workUserAndDir
 }
 
+// getAnswerDescription 获取回答说明，填入当前支持的参数类型，包括配置文件中的自定义类型
+func getAnswerDescription() string {
+	return strings.Replace(answerDescription, "{paramTypes}", strings.Join(validate.Types(), ","), 1)
+}
+
 func getWorkFlow(enablePlatformPerception bool, enableWorkUserAndDir bool) string {
 	// 重置所有步骤为默认状态
 	for i := range workFlowSteps {
@@ -194,7 +205,7 @@ func CreateOnceMessagesFromTemplate(question string, enableExplain bool, enableE
 		"workFlow":          getWorkFlow(enablePlatformPerception, enableWorkUserAndDir),
 		"workPlatform":      getWorkPlatform(enablePlatformPerception),
 		"workUserAndDir":    getWorkUserAndDir(enableWorkUserAndDir),
-		"answerDescription": answerDescription,
+		"answerDescription": getAnswerDescription(),
 		"answerFormat":      getAnswerFormat(enableExplain, enableExtendParams),
 		"question":          question,
 		// 对话历史
@@ -215,7 +226,7 @@ func CreateMoreMessagesFromTemplate(question string, chatHistory []*schema.Messa
 		"workFlow":          getWorkFlow(enablePlatformPerception, enableWorkUserAndDir),
 		"workPlatform":      getWorkPlatform(enablePlatformPerception),
 		"workUserAndDir":    getWorkUserAndDir(enableWorkUserAndDir),
-		"answerDescription": answerDescription,
+		"answerDescription": getAnswerDescription(),
 		"answerFormat":      getAnswerFormat(enableExplain, enableExtendParams),
 		"question":          question,
 		// 对话历史
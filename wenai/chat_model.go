@@ -0,0 +1,48 @@
+package wenai
+
+import (
+	"context"
+	"fmt"
+	wenmodel "wen-ai-cli/model"
+	"wen-ai-cli/setup"
+	"wen-ai-cli/wenai/cassette"
+
+	"github.com/cloudwego/eino/components/model"
+)
+
+// NewChatModel 根据配置档的服务类型创建聊天模型，开启录制或回放时返回对应的 cassette 模型
+func NewChatModel(ctx context.Context, profile *wenmodel.Profile) (model.BaseChatModel, error) {
+	if path := setup.GetReplayCassette(); path != "" {
+		player, err := cassette.OpenPlayer(path)
+		if err != nil {
+			return nil, err
+		}
+		return player, nil
+	}
+	chatModel, err := newProviderChatModel(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	if path := setup.GetRecordCassette(); path != "" {
+		return cassette.NewRecorder(chatModel, path), nil
+	}
+	return chatModel, nil
+}
+
+// newProviderChatModel 根据配置档的服务类型创建聊天模型
+func newProviderChatModel(ctx context.Context, profile *wenmodel.Profile) (model.BaseChatModel, error) {
+	httpClient, err := newHTTPClient(profile.Network)
+	if err != nil {
+		return nil, err
+	}
+	switch profile.GetProvider() {
+	case wenmodel.ProviderOpenAI:
+		return newOpenAIChatModel(ctx, profile, httpClient)
+	case wenmodel.ProviderOllama:
+		return newOllamaChatModel(ctx, profile, httpClient)
+	case wenmodel.ProviderAnthropic:
+		return newAnthropicChatModel(ctx, profile, httpClient)
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", profile.Provider)
+	}
+}
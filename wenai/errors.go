@@ -0,0 +1,153 @@
+package wenai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"wen-ai-cli/wenai/anthropic"
+	"wen-ai-cli/wenai/ollama"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/gookit/i18n"
+)
+
+// ErrorKind 大模型调用错误的分类
+type ErrorKind int
+
+const (
+	ErrorKindUnknown   ErrorKind = iota // 未知错误
+	ErrorKindAuth                       // 认证失败，apiKey 错误或无权限
+	ErrorKindRateLimit                  // 触发限流或额度不足
+	ErrorKindNetwork                    // 网络错误，连接失败、超时或连接中断
+	ErrorKindBadModel                   // 模型不存在或不可用
+	ErrorKindServer                     // 服务端错误
+	ErrorKindCanceled                   // 用户取消
+)
+
+// 各类错误对应的进程退出码
+var exitCodes = map[ErrorKind]int{
+	ErrorKindUnknown:   1,
+	ErrorKindAuth:      3,
+	ErrorKindRateLimit: 4,
+	ErrorKindNetwork:   5,
+	ErrorKindBadModel:  6,
+	ErrorKindServer:    7,
+	ErrorKindCanceled:  130,
+}
+
+// 各类错误对应的多语言提示
+var messageKeys = map[ErrorKind]string{
+	ErrorKindUnknown:   "llmErrorUnknown",
+	ErrorKindAuth:      "llmErrorAuth",
+	ErrorKindRateLimit: "llmErrorRateLimit",
+	ErrorKindNetwork:   "llmErrorNetwork",
+	ErrorKindBadModel:  "llmErrorBadModel",
+	ErrorKindServer:    "llmErrorServer",
+	ErrorKindCanceled:  "llmErrorCanceled",
+}
+
+// Error 分类后的大模型调用错误
+type Error struct {
+	Kind       ErrorKind
+	StatusCode int           // HTTP 状态码，非 HTTP 错误时为 0
+	RetryAfter time.Duration // 服务端通过 Retry-After 建议的重试等待时间
+	Err        error         // 原始错误
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable 返回该错误是否值得重试
+func (e *Error) Retryable() bool {
+	switch e.Kind {
+	case ErrorKindRateLimit, ErrorKindNetwork, ErrorKindServer:
+		return true
+	}
+	return false
+}
+
+// ExitCode 返回该错误对应的进程退出码
+func (e *Error) ExitCode() int {
+	return exitCodes[e.Kind]
+}
+
+// Message 返回面向用户的多语言错误提示
+func (e *Error) Message() string {
+	return fmt.Sprintf(i18n.Dtr(messageKeys[e.Kind]), e.Err)
+}
+
+// statusCodePattern 用于从未知类型的错误信息中提取状态码
+var statusCodePattern = regexp.MustCompile(`status code: (\d{3})`)
+
+// ClassifyError 将各服务类型返回的原始错误转换为分类后的 Error
+func ClassifyError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var wenErr *Error
+	if errors.As(err, &wenErr) {
+		return wenErr
+	}
+
+	e := &Error{Kind: ErrorKindUnknown, Err: err}
+	var message string
+	var (
+		openaiErr    *openai.APIError
+		ollamaErr    *ollama.APIError
+		anthropicErr *anthropic.APIError
+		netErr       net.Error
+		pathErr      *fs.PathError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		e.Kind = ErrorKindCanceled
+		return e
+	case errors.As(err, &openaiErr):
+		e.StatusCode = openaiErr.HTTPStatusCode
+		message = openaiErr.Message
+	case errors.As(err, &ollamaErr):
+		e.StatusCode = ollamaErr.StatusCode
+		message = ollamaErr.Message
+	case errors.As(err, &anthropicErr):
+		e.StatusCode = anthropicErr.StatusCode
+		message = anthropicErr.Message
+	case errors.As(err, &pathErr):
+		// 读取证书等本地文件失败。*fs.PathError 本身不是 net.Error，但 errors.As 会沿 Unwrap 找到其中的 syscall.Errno，
+		// syscall.Errno 实现了 Timeout 和 Temporary，满足 net.Error，因此需要在网络错误之前排除
+		return e
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &netErr):
+		e.Kind = ErrorKindNetwork
+		return e
+	default:
+		if match := statusCodePattern.FindStringSubmatch(err.Error()); match != nil {
+			e.StatusCode, _ = strconv.Atoi(match[1])
+		}
+		message = err.Error()
+	}
+
+	switch {
+	case e.StatusCode == 401 || e.StatusCode == 403:
+		e.Kind = ErrorKindAuth
+	case e.StatusCode == 429:
+		e.Kind = ErrorKindRateLimit
+	case e.StatusCode == 404:
+		e.Kind = ErrorKindBadModel
+	case e.StatusCode == 400 && strings.Contains(strings.ToLower(message), "model"):
+		e.Kind = ErrorKindBadModel
+	case e.StatusCode >= 500:
+		e.Kind = ErrorKindServer
+	}
+	return e
+}
@@ -0,0 +1,83 @@
+package wenai
+
+import (
+	"context"
+	"net/http"
+	"wen-ai-cli/logger"
+	wenmodel "wen-ai-cli/model"
+	"wen-ai-cli/setup"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/gookit/i18n"
+)
+
+// Candidate 回答时依次尝试的配置档
+type Candidate struct {
+	ProfileName string
+	Profile     *wenmodel.Profile
+}
+
+// GetCandidates 获取本次运行依次尝试的配置档，首个为当前配置档，其后为配置的备用配置档，
+// 不存在或信息不完整的备用配置档会被跳过
+func GetCandidates() ([]Candidate, error) {
+	profile, err := setup.GetActiveProfile()
+	if err != nil {
+		return nil, err
+	}
+	candidates := []Candidate{{ProfileName: setup.GetActiveProfileName(), Profile: profile}}
+	for _, fallback := range setup.GetConfig().Fallbacks {
+		fallbackProfile, err := setup.GetProfile(fallback.Profile)
+		if err != nil {
+			logger.Warnf("skip fallback: %v", err)
+			continue
+		}
+		if fallback.Model != "" {
+			fallbackProfile.Model = fallback.Model
+		}
+		if !fallbackProfile.IsComplete() {
+			logger.Warnf(i18n.Dtr("profileIncomplete"), fallback.Profile)
+			continue
+		}
+		candidates = append(candidates, Candidate{ProfileName: fallback.Profile, Profile: fallbackProfile})
+	}
+	return candidates, nil
+}
+
+// StreamWithFallback 依次使用候选配置档创建模型并发起流式请求，
+// 创建模型或建立请求遇到可重试错误时切换到下一个候选，返回实际回答的候选
+func StreamWithFallback(ctx context.Context, candidates []Candidate, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], *Candidate, error) {
+	var lastErr *Error
+	for i := range candidates {
+		candidate := &candidates[i]
+		if i > 0 {
+			logger.Warnf(i18n.Dtr("llmFallback"), candidates[i-1].ProfileName, lastErr.Message(),
+				candidate.ProfileName, candidate.Profile.GetModel())
+		}
+		sr, err := streamWithProfile(ctx, candidate.Profile, in, opts...)
+		if err == nil {
+			logger.Debugf("answered by profile %s, model %s", candidate.ProfileName, candidate.Profile.GetModel())
+			return sr, candidate, nil
+		}
+		lastErr = ClassifyError(err)
+		if !lastErr.Retryable() {
+			return nil, candidate, lastErr
+		}
+	}
+	return nil, &candidates[len(candidates)-1], lastErr
+}
+
+// streamWithProfile 使用指定配置档创建模型并发起流式请求，
+// 服务端因不支持工具调用而拒绝请求时，去掉调用选项再请求一次
+func streamWithProfile(ctx context.Context, profile *wenmodel.Profile, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	chatModel, err := NewChatModel(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	sr, err := Stream(ctx, chatModel, in, opts...)
+	if err != nil && len(opts) > 0 && ClassifyError(err).StatusCode == http.StatusBadRequest {
+		logger.Debugf("request with tools rejected, retry without tools: %v", err)
+		return Stream(ctx, chatModel, in)
+	}
+	return sr, err
+}
@@ -2,34 +2,90 @@ package wenai
 
 import (
 	"context"
-	"log"
+	"time"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/setup"
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
+	"github.com/gookit/i18n"
+)
+
+// 重试配置未填写时的默认值
+const (
+	defaultMaxRetries   = 3
+	defaultInitialDelay = time.Second
+	defaultMaxDelay     = 30 * time.Second
 )
 
 // Generate 使用大语言模型生成回复
 // ctx: 上下文
 // llm: 大语言模型实例
 // in: 输入的消息列表
-// 返回: 生成的回复消息
-func Generate(ctx context.Context, llm model.BaseChatModel, in []*schema.Message) *schema.Message {
-	result, err := llm.Generate(ctx, in)
-	if err != nil {
-		log.Fatalf("llm generate failed: %v", err)
-	}
-	return result
+// 返回: 生成的回复消息，失败时返回分类后的 *Error
+func Generate(ctx context.Context, llm model.BaseChatModel, in []*schema.Message) (*schema.Message, error) {
+	return withRetry(ctx, func(ctx context.Context) (*schema.Message, error) {
+		return llm.Generate(ctx, in)
+	})
 }
 
 // Stream 使用大语言模型进行流式生成回复
 // ctx: 上下文
 // llm: 大语言模型实例
 // in: 输入的消息列表
-// 返回: 生成的流式回复读取器
-func Stream(ctx context.Context, llm model.BaseChatModel, in []*schema.Message) *schema.StreamReader[*schema.Message] {
-	result, err := llm.Stream(ctx, in)
-	if err != nil {
-		log.Fatalf("llm generate failed: %v", err)
+// opts: 调用选项，例如绑定的工具
+// 返回: 生成的流式回复读取器，失败时返回分类后的 *Error
+func Stream(ctx context.Context, llm model.BaseChatModel, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return withRetry(ctx, func(ctx context.Context) (*schema.StreamReader[*schema.Message], error) {
+		return llm.Stream(ctx, in, opts...)
+	})
+}
+
+// withRetry 对可重试的错误按指数退避重试，服务端返回 Retry-After 时优先使用
+func withRetry[T any](ctx context.Context, call func(ctx context.Context) (T, error)) (T, error) {
+	maxRetries, delay, maxDelay := getRetryConfig()
+	ctx, hint := withRetryAfterHint(ctx)
+	for attempt := 0; ; attempt++ {
+		result, err := call(ctx)
+		if err == nil {
+			return result, nil
+		}
+		wenErr := ClassifyError(err)
+		wenErr.RetryAfter = hint.take()
+		if !wenErr.Retryable() || attempt >= maxRetries {
+			return result, wenErr
+		}
+
+		wait := min(delay, maxDelay)
+		if wenErr.RetryAfter > 0 {
+			wait = min(wenErr.RetryAfter, maxDelay)
+		}
+		logger.Warnf(i18n.Dtr("llmRetrying"), wait, attempt+1, maxRetries, wenErr.Message())
+		select {
+		case <-ctx.Done():
+			return result, ClassifyError(ctx.Err())
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// getRetryConfig 读取重试配置，未填写的字段使用默认值
+func getRetryConfig() (int, time.Duration, time.Duration) {
+	retry := setup.GetConfig().Retry
+	maxRetries := retry.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	} else if maxRetries < 0 {
+		maxRetries = 0
+	}
+	delay := time.Duration(retry.InitialDelayMs) * time.Millisecond
+	if delay <= 0 {
+		delay = defaultInitialDelay
+	}
+	maxDelay := time.Duration(retry.MaxDelayMs) * time.Millisecond
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxDelay
 	}
-	return result
+	return maxRetries, delay, maxDelay
 }
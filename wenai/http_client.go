@@ -0,0 +1,155 @@
+package wenai
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	wenmodel "wen-ai-cli/model"
+)
+
+// retryAfterKey 在上下文中保存 Retry-After 提示的 key
+type retryAfterKey struct{}
+
+// retryAfterHint 记录最近一次限流响应中的 Retry-After
+type retryAfterHint struct {
+	mu    sync.Mutex
+	delay time.Duration
+}
+
+func (h *retryAfterHint) set(delay time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.delay = delay
+}
+
+// take 读取并清空记录的等待时间
+func (h *retryAfterHint) take() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delay := h.delay
+	h.delay = 0
+	return delay
+}
+
+// withRetryAfterHint 在上下文中挂载 Retry-After 记录，供 retryAfterTransport 回填
+func withRetryAfterHint(ctx context.Context) (context.Context, *retryAfterHint) {
+	hint := &retryAfterHint{}
+	return context.WithValue(ctx, retryAfterKey{}, hint), hint
+}
+
+// retryAfterTransport 在 429/503 响应中读取 Retry-After 请求头，
+// 各服务类型的 SDK 不会透出响应头，因此在传输层记录
+type retryAfterTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if hint, ok := req.Context().Value(retryAfterKey{}).(*retryAfterHint); ok {
+			hint.set(parseRetryAfter(resp.Header.Get("Retry-After")))
+		}
+	}
+	return resp, nil
+}
+
+// parseRetryAfter 解析秒数或 HTTP 日期两种格式的 Retry-After
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(t); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// headerTransport 为每个请求附加配置的请求头
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper 不应修改原请求
+	req = req.Clone(req.Context())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newHTTPClient 按配置档的网络选项创建各服务类型共用的 HTTP 客户端
+func newHTTPClient(options wenmodel.NetworkOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if options.Proxy != "" {
+		proxyURL, err := url.Parse(options.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", options.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	tlsConfig, err := newTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	var base http.RoundTripper = transport
+	if len(options.Headers) > 0 {
+		base = &headerTransport{base: base, headers: options.Headers}
+	}
+	return &http.Client{
+		Transport: &retryAfterTransport{base: base},
+	}, nil
+}
+
+// newTLSConfig 根据网络选项创建 TLS 配置，未配置任何 TLS 选项时返回 nil 使用默认配置
+func newTLSConfig(options wenmodel.NetworkOptions) (*tls.Config, error) {
+	if options.CAFile == "" && options.CertFile == "" && options.KeyFile == "" && !options.InsecureSkipVerify {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+	if options.CAFile != "" {
+		pem, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read caFile: %w", err)
+		}
+		// 在系统证书的基础上追加，系统证书不可用时只信任配置的 CA
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in caFile %s", options.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if options.CertFile != "" || options.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
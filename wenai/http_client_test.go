@@ -0,0 +1,228 @@
+package wenai
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	wenmodel "wen-ai-cli/model"
+)
+
+// okHandler 返回 ok，用于校验请求是否到达服务端
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	io.WriteString(w, "ok")
+})
+
+// writePEM 将 PEM 内容写入临时文件并返回路径
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// serverCAFile 将测试服务的证书写入 CA 文件
+func serverCAFile(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	return writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+}
+
+// get 使用按网络选项创建的客户端请求 url
+func get(t *testing.T, options wenmodel.NetworkOptions, url string) (string, error) {
+	t.Helper()
+	client, err := newHTTPClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+// newClientCert 生成自签名的客户端 CA 以及由它签发的客户端证书，返回 CA 证书和证书、私钥文件路径
+func newClientCert(t *testing.T) (*x509.Certificate, string, string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test client ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "wen"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return caCert, writePEM(t, "client.pem", "CERTIFICATE", der), writePEM(t, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+func TestHTTPClientCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(okHandler)
+	defer server.Close()
+
+	body, err := get(t, wenmodel.NetworkOptions{CAFile: serverCAFile(t, server)}, server.URL)
+	if err != nil || body != "ok" {
+		t.Fatalf("custom CA: body %q, err %v", body, err)
+	}
+}
+
+func TestHTTPClientUntrustedCert(t *testing.T) {
+	server := httptest.NewTLSServer(okHandler)
+	defer server.Close()
+	// httptest 的服务共用同一张内置证书，另一个 CA 需要自行生成
+	otherCA, _, _ := newClientCert(t)
+
+	tests := []struct {
+		name    string
+		options wenmodel.NetworkOptions
+	}{
+		{"system roots", wenmodel.NetworkOptions{}},
+		{"other CA", wenmodel.NetworkOptions{CAFile: writePEM(t, "other.pem", "CERTIFICATE", otherCA.Raw)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := get(t, tt.options, server.URL)
+			if err == nil || !strings.Contains(err.Error(), "certificate") {
+				t.Fatalf("err = %v, want a certificate error", err)
+			}
+		})
+	}
+}
+
+func TestHTTPClientInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(okHandler)
+	defer server.Close()
+
+	body, err := get(t, wenmodel.NetworkOptions{InsecureSkipVerify: true}, server.URL)
+	if err != nil || body != "ok" {
+		t.Fatalf("insecureSkipVerify: body %q, err %v", body, err)
+	}
+}
+
+func TestHTTPClientCertificate(t *testing.T) {
+	clientCA, certFile, keyFile := newClientCert(t)
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCA)
+	server := httptest.NewUnstartedServer(okHandler)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+	caFile := serverCAFile(t, server)
+
+	body, err := get(t, wenmodel.NetworkOptions{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}, server.URL)
+	if err != nil || body != "ok" {
+		t.Fatalf("client certificate: body %q, err %v", body, err)
+	}
+	// 未配置客户端证书时服务端拒绝握手
+	if _, err := get(t, wenmodel.NetworkOptions{CAFile: caFile}, server.URL); err == nil {
+		t.Fatal("request without a client certificate should fail")
+	}
+}
+
+func TestHTTPClientInvalidTLSFiles(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, options := range []wenmodel.NetworkOptions{
+		{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		{CAFile: notPEM},
+		{CertFile: notPEM, KeyFile: notPEM},
+		// 只配置证书或私钥中的一个时报告缺少的文件，不能忽略客户端证书配置
+		{CertFile: notPEM},
+		{KeyFile: notPEM},
+	} {
+		if _, err := newHTTPClient(options); err == nil {
+			t.Errorf("newHTTPClient(%+v) should fail", options)
+		}
+	}
+}
+
+func TestHTTPClientHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	headers := map[string]string{"X-Tenant-Id": "team-a", "User-Agent": "wen-test"}
+	if _, err := get(t, wenmodel.NetworkOptions{Headers: headers}, server.URL); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range headers {
+		if got.Get(key) != value {
+			t.Errorf("header %s = %q, want %q", key, got.Get(key), value)
+		}
+	}
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	// 代理收到的是完整的目标地址，按目标地址返回内容即可验证请求经过了代理
+	var target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.URL.String()
+		io.WriteString(w, "via proxy")
+	}))
+	defer proxy.Close()
+
+	body, err := get(t, wenmodel.NetworkOptions{Proxy: proxy.URL}, "http://llm.example.test/v1/models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "via proxy" || target != "http://llm.example.test/v1/models" {
+		t.Errorf("body %q, proxied target %q", body, target)
+	}
+
+	if _, err := newHTTPClient(wenmodel.NetworkOptions{Proxy: "http://[::1"}); err == nil {
+		t.Error("invalid proxy should fail")
+	}
+}
@@ -0,0 +1,104 @@
+package wenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	wenmodel "wen-ai-cli/model"
+	"wen-ai-cli/wenai/anthropic"
+	"wen-ai-cli/wenai/ollama"
+
+	"github.com/gookit/i18n"
+)
+
+// ModelInfo 模型服务提供的模型
+type ModelInfo struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"` // 展示用的附加信息，例如所属组织、显示名称或大小
+}
+
+// ListModels 获取配置档对应服务的可用模型列表，按名称排序
+func ListModels(ctx context.Context, profile *wenmodel.Profile) ([]ModelInfo, error) {
+	httpClient, err := newHTTPClient(profile.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []ModelInfo
+	switch profile.GetProvider() {
+	case wenmodel.ProviderOpenAI:
+		if profile.Azure.Enabled {
+			return nil, errors.New(i18n.Dtr("modelsAzureUnsupported"))
+		}
+		models, err = listOpenAIModels(ctx, profile, httpClient)
+	case wenmodel.ProviderOllama:
+		var tags []ollama.ModelInfo
+		tags, err = ollama.ListModels(ctx, &ollama.Config{BaseURL: profile.BaseURL, HTTPClient: httpClient})
+		for _, tag := range tags {
+			models = append(models, ModelInfo{ID: tag.Name, Description: fmt.Sprintf("%.1f GB", float64(tag.Size)/1e9)})
+		}
+	case wenmodel.ProviderAnthropic:
+		var list []anthropic.ModelInfo
+		list, err = anthropic.ListModels(ctx, &anthropic.Config{
+			BaseURL:    profile.BaseURL,
+			APIKey:     profile.APIKey,
+			Version:    profile.Anthropic.Version,
+			HTTPClient: httpClient,
+		})
+		for _, m := range list {
+			models = append(models, ModelInfo{ID: m.ID, Description: m.DisplayName})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", profile.Provider)
+	}
+	if err != nil {
+		return nil, ClassifyError(err)
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].ID < models[j].ID
+	})
+	return models, nil
+}
+
+// listOpenAIModels 通过 OpenAI 兼容接口的 /models 获取模型列表
+func listOpenAIModels(ctx context.Context, profile *wenmodel.Profile, httpClient *http.Client) ([]ModelInfo, error) {
+	url := strings.TrimRight(profile.BaseURL, "/") + "/models"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if profile.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+profile.APIKey)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		// 保持与 SDK 错误相同的格式，交给 ClassifyError 按状态码分类
+		return nil, fmt.Errorf("list models failed, status code: %d, message: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	var list struct {
+		Data []struct {
+			ID      string `json:"id"`
+			OwnedBy string `json:"owned_by"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("decode models failed: %w", err)
+	}
+	models := make([]ModelInfo, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, ModelInfo{ID: m.ID, Description: m.OwnedBy})
+	}
+	return models, nil
+}
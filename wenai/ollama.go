@@ -0,0 +1,60 @@
+package wenai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"wen-ai-cli/logger"
+	wenmodel "wen-ai-cli/model"
+	"wen-ai-cli/wenai/ollama"
+
+	"github.com/cloudwego/eino/components/model"
+)
+
+// newOllamaChatModel 创建 Ollama 原生接口的聊天模型，按需自动拉取模型
+func newOllamaChatModel(ctx context.Context, profile *wenmodel.Profile, httpClient *http.Client) (model.BaseChatModel, error) {
+	cm, err := ollama.NewChatModel(ctx, &ollama.Config{
+		BaseURL:    profile.BaseURL,
+		Model:      profile.Model,
+		KeepAlive:  profile.Ollama.KeepAlive,
+		NumCtx:     profile.Ollama.NumCtx,
+		HTTPClient: httpClient,
+		// 生成参数
+		Temperature: profile.Generation.Temperature,
+		TopP:        profile.Generation.TopP,
+		MaxTokens:   profile.Generation.MaxTokens,
+		Stop:        profile.Generation.Stop,
+		Seed:        profile.Generation.Seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !profile.Ollama.AutoPull {
+		return cm, nil
+	}
+
+	exists, err := cm.HasModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return cm, nil
+	}
+	logger.Infof("pulling ollama model %s", profile.Model)
+	lastStatus := ""
+	err = cm.Pull(ctx, func(p ollama.PullProgress) {
+		// 下载中只在状态变化时输出，避免刷屏
+		status := p.Status
+		if p.Total > 0 {
+			status = fmt.Sprintf("%s %d%%", p.Status, p.Completed*100/p.Total)
+		}
+		if status != lastStatus {
+			logger.Info(status)
+			lastStatus = status
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
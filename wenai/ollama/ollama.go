@@ -0,0 +1,258 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// DefaultBaseURL Ollama 服务默认地址
+const DefaultBaseURL = "http://localhost:11434"
+
+var _ model.BaseChatModel = (*ChatModel)(nil)
+
+// Config Ollama 聊天模型配置
+type Config struct {
+	BaseURL    string       // 服务地址，例如 http://localhost:11434
+	Model      string       // 模型名称，例如 qwen2.5:7b
+	KeepAlive  string       // 模型在内存中的保留时长
+	NumCtx     int          // 上下文窗口大小
+	HTTPClient *http.Client // 自定义 HTTP 客户端，为空时使用 http.DefaultClient
+
+	// 生成参数，调用时传入的选项优先
+	Temperature *float32
+	TopP        *float32
+	MaxTokens   *int
+	Stop        []string
+	Seed        *int
+}
+
+// APIError Ollama 接口返回的错误
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("ollama error, status code: %d, message: %s", e.StatusCode, e.Message)
+}
+
+// ChatModel 基于 Ollama 原生 /api/chat 接口的聊天模型
+type ChatModel struct {
+	conf *Config
+	cli  *http.Client
+}
+
+// NewChatModel 创建 Ollama 聊天模型
+func NewChatModel(ctx context.Context, conf *Config) (*ChatModel, error) {
+	if conf == nil || conf.Model == "" {
+		return nil, errors.New("ollama: model is required")
+	}
+	return newChatModel(conf), nil
+}
+
+// newChatModel 填充配置默认值并创建聊天模型，不校验模型名称
+func newChatModel(conf *Config) *ChatModel {
+	c := *conf
+	if c.BaseURL == "" {
+		c.BaseURL = DefaultBaseURL
+	}
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	cli := c.HTTPClient
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	return &ChatModel{conf: &c, cli: cli}
+}
+
+type chatMessage struct {
+	Role     string `json:"role"`
+	Content  string `json:"content"`
+	Thinking string `json:"thinking,omitempty"`
+}
+
+type chatRequest struct {
+	Model     string         `json:"model"`
+	Messages  []chatMessage  `json:"messages"`
+	Stream    bool           `json:"stream"`
+	KeepAlive string         `json:"keep_alive,omitempty"`
+	Options   map[string]any `json:"options,omitempty"`
+}
+
+type chatResponse struct {
+	Message         chatMessage `json:"message"`
+	Done            bool        `json:"done"`
+	DoneReason      string      `json:"done_reason"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+	Error           string      `json:"error"`
+}
+
+// Generate 非流式生成回复
+func (cm *ChatModel) Generate(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	resp, err := cm.doChat(ctx, in, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("ollama: decode response failed: %w", err)
+	}
+	if out.Error != "" {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: out.Error}
+	}
+	return toMessage(&out), nil
+}
+
+// Stream 流式生成回复，Ollama 以逐行 JSON 的形式返回增量内容
+func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	resp, err := cm.doChat(ctx, in, true, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer resp.Body.Close()
+		defer sw.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var chunk chatResponse
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				sw.Send(nil, fmt.Errorf("ollama: decode stream chunk failed: %w", err))
+				return
+			}
+			if chunk.Error != "" {
+				sw.Send(nil, &APIError{StatusCode: resp.StatusCode, Message: chunk.Error})
+				return
+			}
+			if closed := sw.Send(toMessage(&chunk), nil); closed {
+				return
+			}
+			if chunk.Done {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			sw.Send(nil, fmt.Errorf("ollama: read stream failed: %w", err))
+		}
+	}()
+	return sr, nil
+}
+
+// doChat 发送 /api/chat 请求，非 2xx 响应转换为 APIError
+func (cm *ChatModel) doChat(ctx context.Context, in []*schema.Message, stream bool, opts ...model.Option) (*http.Response, error) {
+	options := model.GetCommonOptions(&model.Options{
+		Model:       &cm.conf.Model,
+		Temperature: cm.conf.Temperature,
+		TopP:        cm.conf.TopP,
+		MaxTokens:   cm.conf.MaxTokens,
+		Stop:        cm.conf.Stop,
+	}, opts...)
+
+	req := &chatRequest{
+		Model:     *options.Model,
+		Stream:    stream,
+		KeepAlive: cm.conf.KeepAlive,
+		Options:   map[string]any{},
+	}
+	for _, msg := range in {
+		req.Messages = append(req.Messages, chatMessage{Role: string(msg.Role), Content: msg.Content})
+	}
+	if cm.conf.NumCtx > 0 {
+		req.Options["num_ctx"] = cm.conf.NumCtx
+	}
+	if options.Temperature != nil {
+		req.Options["temperature"] = *options.Temperature
+	}
+	if options.TopP != nil {
+		req.Options["top_p"] = *options.TopP
+	}
+	if options.MaxTokens != nil {
+		req.Options["num_predict"] = *options.MaxTokens
+	}
+	if len(options.Stop) > 0 {
+		req.Options["stop"] = options.Stop
+	}
+	if cm.conf.Seed != nil {
+		req.Options["seed"] = *cm.conf.Seed
+	}
+	if len(req.Options) == 0 {
+		req.Options = nil
+	}
+
+	return cm.post(ctx, "/api/chat", req)
+}
+
+// post 以 JSON 请求体调用 Ollama 接口
+func (cm *ChatModel) post(ctx context.Context, path string, body any) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cm.conf.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := cm.cli.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+	return resp, nil
+}
+
+// newAPIError 从错误响应中读取 Ollama 的错误信息
+func newAPIError(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(raw))
+	if json.Unmarshal(raw, &body) == nil && body.Error != "" {
+		message = body.Error
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: message}
+}
+
+// toMessage 将 Ollama 响应转换为 eino 消息
+func toMessage(resp *chatResponse) *schema.Message {
+	msg := &schema.Message{
+		Role:             schema.Assistant,
+		Content:          resp.Message.Content,
+		ReasoningContent: resp.Message.Thinking,
+	}
+	if resp.Done {
+		msg.ResponseMeta = &schema.ResponseMeta{
+			FinishReason: resp.DoneReason,
+			Usage: &schema.TokenUsage{
+				PromptTokens:     resp.PromptEvalCount,
+				CompletionTokens: resp.EvalCount,
+				TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+			},
+		}
+	}
+	return msg
+}
@@ -0,0 +1,176 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// newTestServer 启动模拟 /api/chat 的服务，按行返回 lines，并记录收到的请求
+func newTestServer(t *testing.T, status int, lines []string, got *chatRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			http.NotFound(w, r)
+			return
+		}
+		if got != nil {
+			if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+				t.Errorf("decode request: %v", err)
+			}
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(status)
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readStream 读取全部流式消息，返回拼接的内容、最后一条消息和读取时的错误
+func readStream(t *testing.T, sr *schema.StreamReader[*schema.Message]) (string, *schema.Message, error) {
+	t.Helper()
+	defer sr.Close()
+	var content strings.Builder
+	var last *schema.Message
+	for {
+		msg, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return content.String(), last, nil
+		}
+		if err != nil {
+			return content.String(), last, err
+		}
+		content.WriteString(msg.Content)
+		last = msg
+	}
+}
+
+func TestStreamChunksAndUsage(t *testing.T) {
+	var req chatRequest
+	server := newTestServer(t, http.StatusOK, []string{
+		`{"message":{"role":"assistant","content":"Hel"},"done":false}`,
+		``,
+		`{"message":{"role":"assistant","content":"lo","thinking":"hmm"},"done":false}`,
+		`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":12,"eval_count":5}`,
+		`{"message":{"role":"assistant","content":"ignored after done"},"done":false}`,
+	}, &req)
+
+	numPredict := 64
+	cm, err := NewChatModel(context.Background(), &Config{BaseURL: server.URL + "/", Model: "qwen", NumCtx: 4096, MaxTokens: &numPredict})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sr, err := cm.Stream(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, last, err := readStream(t, sr)
+	if err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+	if content != "Hello" {
+		t.Errorf("content = %q, want %q", content, "Hello")
+	}
+	if last.ResponseMeta == nil || last.ResponseMeta.Usage == nil {
+		t.Fatalf("last chunk has no usage: %+v", last)
+	}
+	usage := last.ResponseMeta.Usage
+	if usage.PromptTokens != 12 || usage.CompletionTokens != 5 || usage.TotalTokens != 17 {
+		t.Errorf("usage = %+v", usage)
+	}
+	if last.ResponseMeta.FinishReason != "stop" {
+		t.Errorf("finish reason = %q", last.ResponseMeta.FinishReason)
+	}
+
+	if !req.Stream || req.Model != "qwen" || len(req.Messages) != 1 || req.Messages[0].Content != "hi" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if req.Options["num_ctx"] != float64(4096) || req.Options["num_predict"] != float64(64) {
+		t.Errorf("unexpected options: %v", req.Options)
+	}
+}
+
+func TestStreamMidStreamError(t *testing.T) {
+	server := newTestServer(t, http.StatusOK, []string{
+		`{"message":{"role":"assistant","content":"partial"},"done":false}`,
+		`{"error":"model runner has unexpectedly stopped"}`,
+	}, nil)
+
+	cm, err := NewChatModel(context.Background(), &Config{BaseURL: server.URL, Model: "qwen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sr, err := cm.Stream(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _, err := readStream(t, sr)
+	if content != "partial" {
+		t.Errorf("content = %q, want %q", content, "partial")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.Message != "model runner has unexpectedly stopped" {
+		t.Errorf("message = %q", apiErr.Message)
+	}
+}
+
+func TestNonSuccessStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		message string
+	}{
+		{"json error", http.StatusNotFound, `{"error":"model \"qwen\" not found, try pulling it first"}`, `model "qwen" not found, try pulling it first`},
+		{"plain text", http.StatusBadGateway, `bad gateway`, `bad gateway`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.status, []string{tt.body}, nil)
+			cm, err := NewChatModel(context.Background(), &Config{BaseURL: server.URL, Model: "qwen"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = cm.Stream(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message != tt.message {
+				t.Errorf("got %d %q, want %d %q", apiErr.StatusCode, apiErr.Message, tt.status, tt.message)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	server := newTestServer(t, http.StatusOK, []string{
+		`{"message":{"role":"assistant","content":"ls -la"},"done":true,"done_reason":"stop","prompt_eval_count":3,"eval_count":2}`,
+	}, nil)
+	cm, err := NewChatModel(context.Background(), &Config{BaseURL: server.URL, Model: "qwen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := cm.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Content != "ls -la" || msg.ResponseMeta.Usage.TotalTokens != 5 {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
@@ -0,0 +1,100 @@
+package ollama
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// PullProgress 模型拉取进度
+type PullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+	Error     string `json:"error"`
+}
+
+// ModelInfo /api/tags 返回的本地模型信息
+type ModelInfo struct {
+	Name  string `json:"name"`
+	Model string `json:"model"`
+	Size  int64  `json:"size"`
+}
+
+// ListModels 通过 /api/tags 获取本地已有的模型列表，配置中的模型名称可以为空
+func ListModels(ctx context.Context, conf *Config) ([]ModelInfo, error) {
+	return newChatModel(conf).listModels(ctx)
+}
+
+// HasModel 通过 /api/tags 判断模型是否已在本地存在
+func (cm *ChatModel) HasModel(ctx context.Context) (bool, error) {
+	models, err := cm.listModels(ctx)
+	if err != nil {
+		return false, err
+	}
+	// 未指定标签的模型名称等价于 latest
+	want := cm.conf.Model
+	if !strings.Contains(want, ":") {
+		want += ":latest"
+	}
+	for _, m := range models {
+		if m.Name == want || m.Model == want {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (cm *ChatModel) listModels(ctx context.Context) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cm.conf.BaseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cm.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, newAPIError(resp)
+	}
+
+	var tags struct {
+		Models []ModelInfo `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("ollama: decode tags failed: %w", err)
+	}
+	return tags.Models, nil
+}
+
+// Pull 通过 /api/pull 拉取模型，并将每条进度回调给 onProgress
+func (cm *ChatModel) Pull(ctx context.Context, onProgress func(PullProgress)) error {
+	resp, err := cm.post(ctx, "/api/pull", map[string]any{
+		"model":  cm.conf.Model,
+		"stream": true,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var progress PullProgress
+		if err := json.Unmarshal(scanner.Bytes(), &progress); err != nil {
+			continue
+		}
+		if progress.Error != "" {
+			return &APIError{StatusCode: resp.StatusCode, Message: progress.Error}
+		}
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+	return scanner.Err()
+}
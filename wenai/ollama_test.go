@@ -0,0 +1,47 @@
+package wenai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wen-ai-cli/wenai/ollama"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestClassifyOllamaError(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		kind   ErrorKind
+	}{
+		{http.StatusUnauthorized, `{"error":"unauthorized"}`, ErrorKindAuth},
+		{http.StatusTooManyRequests, `{"error":"too many requests"}`, ErrorKindRateLimit},
+		{http.StatusNotFound, `{"error":"model \"qwen\" not found, try pulling it first"}`, ErrorKindBadModel},
+		{http.StatusBadRequest, `{"error":"invalid model name"}`, ErrorKindBadModel},
+		{http.StatusInternalServerError, `{"error":"llama runner process has terminated"}`, ErrorKindServer},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cm, err := ollama.NewChatModel(context.Background(), &ollama.Config{BaseURL: server.URL, Model: "qwen"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = cm.Stream(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			wenErr := ClassifyError(err)
+			if wenErr.Kind != tt.kind || wenErr.StatusCode != tt.status {
+				t.Errorf("ClassifyError() = kind %d status %d, want kind %d status %d", wenErr.Kind, wenErr.StatusCode, tt.kind, tt.status)
+			}
+		})
+	}
+}
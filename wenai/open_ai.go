@@ -2,24 +2,35 @@ package wenai
 
 import (
 	"context"
-	"log"
+	"net/http"
+	wenmodel "wen-ai-cli/model"
 
 	"github.com/cloudwego/eino-ext/components/model/openai"
 	"github.com/cloudwego/eino/components/model"
-	"github.com/gookit/config/v2"
 )
 
-func CreateOpenAIChatModel(ctx context.Context) model.BaseChatModel {
-	apiKey := config.String("openai.apiKey")
-	baseURL := config.String("openai.baseURL")
-	modelName := config.String("openai.model")
-	chatModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
-		BaseURL: baseURL,
-		Model:   modelName,
-		APIKey:  apiKey,
-	})
-	if err != nil {
-		log.Fatalf("create openai chat model failed, err=%v", err)
+// newOpenAIChatModel 创建 OpenAI 兼容接口的聊天模型，支持 Azure OpenAI 部署
+func newOpenAIChatModel(ctx context.Context, profile *wenmodel.Profile, httpClient *http.Client) (model.BaseChatModel, error) {
+	conf := &openai.ChatModelConfig{
+		BaseURL:    profile.BaseURL,
+		Model:      profile.GetModel(),
+		APIKey:     profile.APIKey,
+		HTTPClient: httpClient,
+		// 生成参数
+		Temperature: profile.Generation.Temperature,
+		MaxTokens:   profile.Generation.MaxTokens,
+		TopP:        profile.Generation.TopP,
+		Stop:        profile.Generation.Stop,
+		Seed:        profile.Generation.Seed,
 	}
-	return chatModel
+	if profile.Azure.Enabled {
+		conf.ByAzure = true
+		conf.APIVersion = profile.Azure.APIVersion
+		if deployment := profile.Azure.Deployment; deployment != "" {
+			conf.AzureModelMapperFunc = func(string) string {
+				return deployment
+			}
+		}
+	}
+	return openai.NewChatModel(ctx, conf)
 }
@@ -0,0 +1,116 @@
+package wenai
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"wen-ai-cli/logger"
+	"wen-ai-cli/model"
+	"wen-ai-cli/placeholder"
+
+	einomodel "github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ProposeCommandToolName 提交最佳脚本的工具名称
+const ProposeCommandToolName = "propose_command"
+
+// ProposeCommandTool 让模型以结构化参数提交最佳脚本，替代从回答文本中正则提取
+var ProposeCommandTool = &schema.ToolInfo{
+	Name: ProposeCommandToolName,
+	Desc: "回答完成后调用此工具提交回答中的最佳脚本。脚本中需要用户补充的值使用 <参数名称,参数类型[,默认值[,说明]]> 占位，并在 parameters 中逐一说明。",
+	ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+		"script": {
+			Type:     schema.String,
+			Desc:     "可直接在目标系统命令行中执行的最佳脚本，需要用户补充的值使用 <参数名称,参数类型[,默认值[,说明]]> 占位，参数类型为 enum:选项1|选项2 时只能从选项中选择",
+			Required: true,
+		},
+		"parameters": {
+			Type: schema.Array,
+			Desc: "脚本中的占位参数，没有占位时为空数组",
+			ElemInfo: &schema.ParameterInfo{
+				Type: schema.Object,
+				SubParams: map[string]*schema.ParameterInfo{
+					"name":        {Type: schema.String, Desc: "参数名称，与脚本占位中的名称一致", Required: true},
+					"type":        {Type: schema.String, Desc: "参数类型，与脚本占位中的类型一致，例如 string、url、number、int:1..100、port、file、enum，密码、令牌等敏感值使用 secret", Required: true},
+					"description": {Type: schema.String, Desc: "参数说明"},
+					"default":     {Type: schema.String, Desc: "参数默认值，没有时省略"},
+					"options":     {Type: schema.Array, Desc: "enum 类型的可选值", ElemInfo: &schema.ParameterInfo{Type: schema.String}},
+				},
+			},
+		},
+		"risks": {
+			Type:     schema.Array,
+			Desc:     "执行脚本可能带来的风险提示，例如删除文件、修改系统配置，没有风险时为空数组",
+			ElemInfo: &schema.ParameterInfo{Type: schema.String},
+		},
+	}),
+}
+
+// ProposeCommandOptions 返回绑定 propose_command 工具的调用选项，enabled 为 false 时返回 nil
+func ProposeCommandOptions(enabled bool) []einomodel.Option {
+	if !enabled {
+		return nil
+	}
+	return []einomodel.Option{einomodel.WithTools([]*schema.ToolInfo{ProposeCommandTool})}
+}
+
+// proposeCommandArgs propose_command 工具的调用参数
+type proposeCommandArgs struct {
+	Script     string `json:"script"`
+	Parameters []struct {
+		Name        string   `json:"name"`
+		Type        string   `json:"type"`
+		Description string   `json:"description"`
+		Default     string   `json:"default"`
+		Options     []string `json:"options"`
+	} `json:"parameters"`
+	Risks []string `json:"risks"`
+}
+
+// parseProposeCommand 从工具调用中解析脚本和参数，模型未调用 propose_command 或参数无法解析时返回 false
+func parseProposeCommand(toolCalls []schema.ToolCall) (*model.HiddenParams, bool) {
+	for _, toolCall := range toolCalls {
+		if toolCall.Function.Name != ProposeCommandToolName {
+			continue
+		}
+		var args proposeCommandArgs
+		if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err != nil {
+			return nil, false
+		}
+		script := strings.TrimSpace(args.Script)
+		if script == "" {
+			return nil, false
+		}
+		result := &model.HiddenParams{
+			Raw:       toolCall.Function.Arguments,
+			ShellCode: script,
+			RiskNotes: args.Risks,
+		}
+		// 以脚本中的占位为准，工具参数中的说明、默认值和选项用于补充占位中未写明的部分
+		result.NeedFillParams = placeholder.Parse(script)
+		for _, param := range args.Parameters {
+			index := slices.IndexFunc(result.NeedFillParams, func(p model.ParamInfo) bool {
+				return p.Param == param.Name
+			})
+			if index < 0 {
+				// 脚本中没有对应的占位，填写的值无处替换，忽略该参数
+				logger.Debugf("propose_command parameter %q has no placeholder in the script, ignored", param.Name)
+				continue
+			}
+			filled := &result.NeedFillParams[index]
+			if filled.Description == "" {
+				filled.Description = param.Description
+			}
+			if filled.Default == "" {
+				filled.Default = param.Default
+			}
+			if len(filled.Options) == 0 && len(param.Options) > 0 {
+				filled.Options = param.Options
+			}
+		}
+		result.Blocks = []model.ScriptBlock{{ShellCode: script, NeedFillParams: result.NeedFillParams}}
+		return result, true
+	}
+	return nil, false
+}
@@ -0,0 +1,50 @@
+package wenai
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestParseProposeCommand(t *testing.T) {
+	arguments := `{
+		"script": "curl -o <文件名,string> <下载地址,url>",
+		"parameters": [
+			{"name": "下载地址", "type": "url", "description": "文件的 URL"},
+			{"name": "文件名", "type": "string", "default": "a.txt"},
+			{"name": "代理", "type": "url", "description": "脚本中没有这个占位"}
+		],
+		"risks": ["覆盖同名文件"]
+	}`
+	result, ok := parseProposeCommand([]schema.ToolCall{
+		{Function: schema.FunctionCall{Name: "other", Arguments: "{}"}},
+		{Function: schema.FunctionCall{Name: ProposeCommandToolName, Arguments: arguments}},
+	})
+	if !ok {
+		t.Fatal("propose_command not parsed")
+	}
+	if result.ShellCode != "curl -o <文件名,string> <下载地址,url>" {
+		t.Errorf("shell code = %q", result.ShellCode)
+	}
+	params := result.NeedFillParams
+	if len(params) != 2 {
+		t.Fatalf("params = %+v, want the two placeholders only", params)
+	}
+	if params[0].Param != "文件名" || params[0].Default != "a.txt" {
+		t.Errorf("params[0] = %+v", params[0])
+	}
+	if params[1].Param != "下载地址" || params[1].Description != "文件的 URL" {
+		t.Errorf("params[1] = %+v", params[1])
+	}
+	if len(result.RiskNotes) != 1 || len(result.Blocks) != 1 {
+		t.Errorf("risks = %v, blocks = %v", result.RiskNotes, result.Blocks)
+	}
+}
+
+func TestParseProposeCommandInvalid(t *testing.T) {
+	for _, arguments := range []string{``, `{"script": "  "}`, `not json`} {
+		if _, ok := parseProposeCommand([]schema.ToolCall{{Function: schema.FunctionCall{Name: ProposeCommandToolName, Arguments: arguments}}}); ok {
+			t.Errorf("arguments %q should not be parsed", arguments)
+		}
+	}
+}
@@ -1,18 +1,33 @@
 package wenai
 
 import (
+	"context"
+	"fmt"
 	"io"
-	"log"
-	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
 	"wen-ai-cli/common"
+	"wen-ai-cli/logger"
 	"wen-ai-cli/model"
 	"wen-ai-cli/setup"
+	"wen-ai-cli/usage"
 
 	"github.com/cloudwego/eino/schema"
+	"github.com/gookit/i18n"
 )
 
-func ReportStream(sr *schema.StreamReader[*schema.Message]) (*schema.Message, *model.HiddenParams, error) {
+// ReportOptions 控制流式输出尾部展示的信息
+type ReportOptions struct {
+	ModelName string // 实际回答的模型名称，用于尾部展示和计费
+	ShowUsage bool   // 是否在尾部展示 token 用量
+	Cached    bool   // 回答是否来自本地缓存
+	Fallback  bool   // 回答是否来自备用配置档
+}
+
+// ReportStream 打印流式回答并解析其中的脚本和参数，ctx 被取消时以中断结束并返回已收到的内容，
+// 返回消息的 ResponseMeta 中带有本次调用的 token 用量
+func ReportStream(ctx context.Context, sr *schema.StreamReader[*schema.Message], opts ReportOptions) (*schema.Message, *model.HiddenParams, error) {
 	defer sr.Close()
 
 	// 创建使用自定义内容颜色的打印器
@@ -20,53 +35,166 @@ func ReportStream(sr *schema.StreamReader[*schema.Message]) (*schema.Message, *m
 
 	i := 0
 	result := &model.HiddenParams{}
-	shellCode := ""
 	fullContentBuilder := strings.Builder{}
+	var tokenUsage *schema.TokenUsage
+	var toolCallChunks []*schema.Message
+
+	// 思考内容只用于展示，不写入回答正文，避免进入对话历史和脚本解析
+	reasoningDisplay := setup.GetConfig().Reasoning.GetDisplay()
+	splitter := &thinkTagSplitter{}
+	// 边接收边解析回答中的代码块和段落
+	parser := &answerParser{}
+	var reasoningStart time.Time
+	reasoningLength := 0
+	reasoningDone := false
+	printReasoning := func(text string) {
+		if reasoningLength == 0 {
+			text = strings.TrimLeft(text, "\r\n")
+		}
+		if text == "" || reasoningDisplay == model.ReasoningHide {
+			return
+		}
+		if reasoningStart.IsZero() {
+			reasoningStart = time.Now()
+		}
+		reasoningLength += utf8.RuneCountInString(text)
+		printer.PrintReasoning(text)
+	}
+	endReasoning := func() {
+		if reasoningStart.IsZero() || reasoningDone {
+			return
+		}
+		reasoningDone = true
+		summary := fmt.Sprintf(i18n.Dtr("reasoningSummary"), time.Since(reasoningStart).Round(100*time.Millisecond), reasoningLength)
+		printer.EndReasoning(summary, reasoningDisplay == model.ReasoningCollapse)
+	}
+	printContent := func(content string) {
+		if content == "" {
+			return
+		}
+		endReasoning()
+		fullContentBuilder.WriteString(content)
+		parser.Feed(content)
+		printer.Print(content)
+	}
 	for {
 		message, err := sr.Recv()
 		if err == io.EOF {
+			reasoning, content := splitter.Flush()
+			printReasoning(reasoning)
+			printContent(content)
+			endReasoning()
+			// 模型调用了 propose_command 时直接使用结构化参数，否则从回答文本中解析
+			proposed, ok := mergeProposeCommand(toolCallChunks)
+			if ok {
+				// 只调用了工具而没有回答文本时，补充展示脚本
+				if strings.TrimSpace(fullContentBuilder.String()) == "" {
+					script := "```code\n" + proposed.ShellCode + "\n```\n"
+					printContent(script)
+				}
+				for _, note := range proposed.RiskNotes {
+					printer.Print("\n⚠ " + note)
+				}
+			}
 			// 处理最后一段
+			if footer := footerText(opts, tokenUsage); footer != "" {
+				printer.SetFooterText(footer)
+			}
 			printer.Print("\n")
 			printer.Flush()
 
-			// 移动正则解析代码到这里
-			re := regexp.MustCompile("(?s)```code(.*?)```")
 			fullContent := fullContentBuilder.String()
-			// 查找如果有多个代码块匹配，则认为最后一个代码块是shellCode
-			matches := re.FindAllStringSubmatch(fullContent, -1)
-			if len(matches) > 0 {
-				// 获取最后一个匹配的代码块
-				lastMatch := matches[len(matches)-1]
-				if len(lastMatch) > 1 {
-					shellCode = strings.TrimSpace(lastMatch[1])
-				}
-			}
-			if shellCode != "" {
-				result.ShellCode = shellCode
-				// 解析shellCode,<下载文件的URL,url>序列化成hideParams
-				re := regexp.MustCompile(`<([\p{Han}a-zA-Z0-9]+),(\w+)>`)
-				matches := re.FindAllStringSubmatch(shellCode, -1)
-				for _, match := range matches {
-					paramName := match[1]
-					paramType := match[2]
-					result.NeedFillParams = append(result.NeedFillParams, model.ParamInfo{
-						Param: paramName,
-						Type:  paramType,
-					})
-				}
+			answer := parser.Finish()
+			if ok {
+				result = proposed
+			} else {
+				// 保留全部代码块，默认选中最后一个代码块
+				result.Blocks = answer.Blocks
+				result.SelectBlock(len(result.Blocks) - 1)
 			}
+			result.Answer = answer
 			fullMessage := &schema.Message{
-				Role:    "assistant",
-				Content: fullContent,
+				Role:         "assistant",
+				Content:      fullContent,
+				ToolCalls:    mergeToolCalls(toolCallChunks),
+				ResponseMeta: &schema.ResponseMeta{Usage: tokenUsage},
 			}
 			return fullMessage, result, nil
 		}
 		if err != nil {
-			log.Fatalf("recv failed: %v", err)
+			// 流式读取中断时保留已收到的内容，交由调用方决定如何处理
+			endReasoning()
+			if ctx.Err() != nil {
+				// 用户按下 Ctrl-C，底层返回的错误类型不一，统一按取消处理
+				err = ctx.Err()
+				printer.SetFooterText(i18n.Dtr("interrupted"))
+			}
+			printer.Print("\n")
+			printer.Flush()
+			fullMessage := &schema.Message{
+				Role:         "assistant",
+				Content:      fullContentBuilder.String(),
+				ResponseMeta: &schema.ResponseMeta{Usage: tokenUsage},
+			}
+			return fullMessage, result, ClassifyError(err)
 		}
-		content := message.Content
-		fullContentBuilder.WriteString(content)
-		printer.Print(content)
+		// 用量通常只出现在最后一个分片中
+		if message.ResponseMeta != nil && message.ResponseMeta.Usage != nil {
+			tokenUsage = message.ResponseMeta.Usage
+		}
+		if len(message.ToolCalls) > 0 {
+			toolCallChunks = append(toolCallChunks, message)
+		}
+		printReasoning(message.ReasoningContent)
+		reasoning, content := splitter.Split(message.Content)
+		printReasoning(reasoning)
+		printContent(content)
 		i++
 	}
 }
+
+// mergeToolCalls 合并流式分片中的工具调用，没有工具调用时返回 nil
+func mergeToolCalls(chunks []*schema.Message) []schema.ToolCall {
+	if len(chunks) == 0 {
+		return nil
+	}
+	merged, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		logger.Debugf("concat tool calls failed: %v", err)
+		return nil
+	}
+	return merged.ToolCalls
+}
+
+// mergeProposeCommand 合并流式分片中的工具调用并解析 propose_command
+func mergeProposeCommand(chunks []*schema.Message) (*model.HiddenParams, bool) {
+	return parseProposeCommand(mergeToolCalls(chunks))
+}
+
+// footerText 根据回答来源和用量生成尾部文本，返回空字符串时使用默认尾部
+func footerText(opts ReportOptions, tokenUsage *schema.TokenUsage) string {
+	if opts.Cached {
+		return setup.CliVersion + " · " + i18n.Dtr("cachedAnswer")
+	}
+	footer := ""
+	if opts.ShowUsage && tokenUsage != nil {
+		footer = usageFooter(opts.ModelName, tokenUsage)
+	}
+	if opts.Fallback {
+		if footer == "" {
+			footer = setup.CliVersion + " · " + opts.ModelName
+		}
+		footer += " · " + i18n.Dtr("fallbackAnswer")
+	}
+	return footer
+}
+
+// usageFooter 生成展示 token 用量和费用的尾部文本
+func usageFooter(modelName string, tokenUsage *schema.TokenUsage) string {
+	footer := fmt.Sprintf("%s · %s · %s", setup.CliVersion, modelName,
+		fmt.Sprintf(i18n.Dtr("usageFooter"), tokenUsage.PromptTokens, tokenUsage.CompletionTokens))
+	if cost, ok := usage.Cost(modelName, tokenUsage.PromptTokens, tokenUsage.CompletionTokens); ok {
+		footer += " · " + usage.FormatCost(cost)
+	}
+	return footer
+}
@@ -0,0 +1,80 @@
+package wenai
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// thinkTagSplitter 拆分正文开头 <think>...</think> 包裹的思考内容，
+// 部分模型不使用 reasoning_content 字段，而是把思考过程写在正文中
+type thinkTagSplitter struct {
+	buffer string
+	state  int
+}
+
+// 拆分状态
+const (
+	thinkStateUnknown  = iota // 尚未确定正文开头是否为思考内容
+	thinkStateThinking        // 处于 <think> 标签内
+	thinkStateContent         // 正文
+)
+
+// Split 输入一段正文分片，返回其中的思考内容和正文，标签被拆分到多个分片时会暂存等待后续分片
+func (s *thinkTagSplitter) Split(text string) (reasoning string, content string) {
+	s.buffer += text
+	for {
+		switch s.state {
+		case thinkStateUnknown:
+			trimmed := strings.TrimLeft(s.buffer, " \t\r\n")
+			if strings.HasPrefix(trimmed, thinkOpenTag) {
+				s.buffer = trimmed[len(thinkOpenTag):]
+				s.state = thinkStateThinking
+				continue
+			}
+			if strings.HasPrefix(thinkOpenTag, trimmed) {
+				// 可能是不完整的开始标签，等待后续分片
+				return "", ""
+			}
+			s.state = thinkStateContent
+			continue
+		case thinkStateThinking:
+			if index := strings.Index(s.buffer, thinkCloseTag); index >= 0 {
+				reasoning = s.buffer[:index]
+				s.buffer = strings.TrimLeft(s.buffer[index+len(thinkCloseTag):], "\r\n")
+				s.state = thinkStateContent
+				_, content = s.Split("")
+				return reasoning, content
+			}
+			// 保留可能是结束标签开头的部分
+			keep := len(s.buffer) - len(thinkCloseTag) + 1
+			if keep <= 0 {
+				return "", ""
+			}
+			for keep > 0 && !utf8.RuneStart(s.buffer[keep]) {
+				keep--
+			}
+			reasoning = s.buffer[:keep]
+			s.buffer = s.buffer[keep:]
+			return reasoning, ""
+		default:
+			content = s.buffer
+			s.buffer = ""
+			return "", content
+		}
+	}
+}
+
+// Flush 流结束时取出暂存的内容
+func (s *thinkTagSplitter) Flush() (reasoning string, content string) {
+	buffer := s.buffer
+	s.buffer = ""
+	if s.state == thinkStateThinking {
+		return buffer, ""
+	}
+	return "", buffer
+}